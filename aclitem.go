@@ -0,0 +1,169 @@
+package pq
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// ACLItem represents a Postgres aclitem, one entry of an access privilege
+// list such as pg_class.relacl, whose text form is
+// "grantee=privileges/grantor", such as "alice=r*w/bob". aclitem and
+// aclitem[] columns are decoded as text and []string; scan them into an
+// ACLItem, or with Array into a []ACLItem, to parse them.
+type ACLItem struct {
+	// The role the privileges are granted to, or "" for PUBLIC.
+	Grantee string
+
+	// The privileges granted, as the letters Postgres uses for them, such
+	// as "arwdDxt"; see the documentation of the GRANT command.
+	Privileges string
+
+	// The subset of Privileges that were granted WITH GRANT OPTION, which
+	// are marked with an asterisk in the text form.
+	GrantOptions string
+
+	// The role that granted the privileges.
+	Grantor string
+}
+
+// Has reports whether the privilege with the letter p is granted.
+func (a ACLItem) Has(p byte) bool {
+	return strings.IndexByte(a.Privileges, p) >= 0
+}
+
+// HasGrantOption reports whether the privilege with the letter p is granted
+// with the grant option.
+func (a ACLItem) HasGrantOption(p byte) bool {
+	return strings.IndexByte(a.GrantOptions, p) >= 0
+}
+
+// String returns the text form of the item.
+func (a ACLItem) String() string {
+	b := appendACLName(nil, a.Grantee)
+	b = append(b, '=')
+	for i := 0; i < len(a.Privileges); i++ {
+		b = append(b, a.Privileges[i])
+		if a.HasGrantOption(a.Privileges[i]) {
+			b = append(b, '*')
+		}
+	}
+	b = append(b, '/')
+	return string(appendACLName(b, a.Grantor))
+}
+
+// Scan implements the Scanner interface.
+func (a *ACLItem) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case ACLItem:
+		*a = v
+		return nil
+	case []byte:
+		return a.scanText(string(v))
+	case string:
+		return a.scanText(v)
+	}
+	return fmt.Errorf("pq: cannot scan %T into ACLItem", value)
+}
+
+func (a *ACLItem) scanText(s string) error {
+	v, err := parseACLItem(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (a ACLItem) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// parseACLItem parses the text of an aclitem, such as "alice=r*w/bob" or,
+// for PUBLIC, "=r/bob".
+func parseACLItem(s string) (ACLItem, error) {
+	var a ACLItem
+	fail := func() (ACLItem, error) {
+		return ACLItem{}, fmt.Errorf("pq: unable to parse aclitem %q", s)
+	}
+
+	var rest string
+	var ok bool
+	if a.Grantee, rest, ok = parseACLName(s); !ok || len(rest) == 0 || rest[0] != '=' {
+		return fail()
+	}
+	rest = rest[1:]
+
+	var privs, opts []byte
+	for len(rest) > 0 && rest[0] != '/' {
+		c := rest[0]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z') {
+			return fail()
+		}
+		privs = append(privs, c)
+		rest = rest[1:]
+		if len(rest) > 0 && rest[0] == '*' {
+			opts = append(opts, c)
+			rest = rest[1:]
+		}
+	}
+	a.Privileges, a.GrantOptions = string(privs), string(opts)
+
+	if len(rest) == 0 {
+		return fail()
+	}
+	if a.Grantor, rest, ok = parseACLName(rest[1:]); !ok || len(rest) != 0 || a.Grantor == "" {
+		return fail()
+	}
+	return a, nil
+}
+
+// parseACLName reads a role name from the start of s, up to an '=' or '/',
+// returning it and what is left of s. Names with characters other than
+// letters, digits and underscores are double-quoted, with double quotes
+// inside them doubled.
+func parseACLName(s string) (name, rest string, ok bool) {
+	var b []byte
+	quoted := false
+	i := 0
+	for ; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' && quoted && i+1 < len(s) && s[i+1] == '"':
+			b = append(b, '"')
+			i++
+		case c == '"':
+			quoted = !quoted
+		case (c == '=' || c == '/') && !quoted:
+			return string(b), s[i:], true
+		default:
+			b = append(b, c)
+		}
+	}
+	return string(b), "", !quoted
+}
+
+// appendACLName appends the role name to b, quoting it if need be.
+func appendACLName(b []byte, name string) []byte {
+	safe := true
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '_') {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return append(b, name...)
+	}
+
+	b = append(b, '"')
+	for i := 0; i < len(name); i++ {
+		if name[i] == '"' {
+			b = append(b, '"')
+		}
+		b = append(b, name[i])
+	}
+	return append(b, '"')
+}
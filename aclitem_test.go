@@ -0,0 +1,93 @@
+package pq
+
+import (
+	"github.com/lib/pq/oid"
+	"reflect"
+	"testing"
+)
+
+func TestParseACLItem(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  ACLItem
+	}{
+		{"alice=arwdDxt/bob", ACLItem{"alice", "arwdDxt", "", "bob"}},
+		{"=r/postgres", ACLItem{"", "r", "", "postgres"}},
+		{"alice=r*w/bob", ACLItem{"alice", "rw", "r", "bob"}},
+		{"alice=r*w*/bob", ACLItem{"alice", "rw", "rw", "bob"}},
+		{"alice=/bob", ACLItem{"alice", "", "", "bob"}},
+		{`"my role"=U/"a=b/c"`, ACLItem{"my role", "U", "", "a=b/c"}},
+		{`"say ""hi"""=c/bob`, ACLItem{`say "hi"`, "c", "", "bob"}},
+	} {
+		var got ACLItem
+		if err := got.Scan([]byte(tt.input)); err != nil {
+			t.Errorf("%q: %s", tt.input, err)
+		} else if got != tt.want {
+			t.Errorf("%q: expected %#v, got %#v", tt.input, tt.want, got)
+		}
+		if s := tt.want.String(); s != tt.input {
+			t.Errorf("expected %q, got %q", tt.input, s)
+		}
+	}
+
+	for _, input := range []string{"", "alice", "alice=r", "alice=r/", "alice=r1/bob", "alice=r/bob/carol", `"alice=r/bob`, "alice=*r/bob"} {
+		if _, err := parseACLItem(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestACLItemPrivileges(t *testing.T) {
+	a := ACLItem{"alice", "rw", "r", "bob"}
+	if !a.Has('r') || !a.Has('w') || a.Has('d') {
+		t.Error("unexpected privileges")
+	}
+	if !a.HasGrantOption('r') || a.HasGrantOption('w') {
+		t.Error("unexpected grant options")
+	}
+}
+
+func TestDecodeACLItemArray(t *testing.T) {
+	in := []byte(`{=r/postgres,"\"my role\"=r*w/postgres"}`)
+	got := mustDecode(t, &parameterStatus{}, in, oid.T__aclitem, formatText)
+	if b, ok := got.([]byte); !ok || string(b) != string(in) {
+		t.Errorf("expected the text %q, got %#v", in, got)
+	}
+
+	var items []ACLItem
+	if err := Array(&items).Scan(got); err != nil {
+		t.Fatal(err)
+	}
+	if want := []ACLItem{{"", "r", "", "postgres"}, {"my role", "rw", "r", "postgres"}}; !reflect.DeepEqual(items, want) {
+		t.Errorf("expected %#v, got %#v", want, items)
+	}
+}
+
+func TestACLItemScan(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var user string
+	var items []ACLItem
+	err := db.QueryRow(`SELECT current_user, ARRAY[
+		'=r/' || quote_ident(current_user),
+		quote_ident(current_user) || '=r*w/' || quote_ident(current_user)
+	]::aclitem[]`).Scan(&user, Array(&items))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ACLItem{{"", "r", "", user}, {user, "rw", "r", user}}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("expected %#v, got %#v", want, items)
+	}
+
+	var s string
+	var strs []string
+	err = db.QueryRow(`SELECT ('=r/' || quote_ident(current_user))::aclitem, ARRAY['=r/' || quote_ident(current_user)]::aclitem[]`).Scan(&s, Array(&strs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "=r/" + user; s != want || !reflect.DeepEqual(strs, []string{want}) {
+		t.Errorf("expected %q, got %q and %q", want, s, strs)
+	}
+}
@@ -0,0 +1,698 @@
+package pq
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"github.com/lib/pq/oid"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	bytesType = reflect.TypeOf([]byte(nil))
+)
+
+// arrayTypes maps the array types that encode understands to the OIDs of
+// their element types.
+var arrayTypes = map[oid.Oid]oid.Oid{
+	oid.T__bool:        oid.T_bool,
+	oid.T__int2:        oid.T_int2,
+	oid.T__int4:        oid.T_int4,
+	oid.T__int8:        oid.T_int8,
+	oid.T__float4:      oid.T_float4,
+	oid.T__float8:      oid.T_float8,
+	oid.T__numeric:     oid.T_numeric,
+	oid.T__text:        oid.T_text,
+	oid.T__varchar:     oid.T_varchar,
+	oid.T__bpchar:      oid.T_bpchar,
+	oid.T__name:        oid.T_name,
+	oid.T__char:        oid.T_char,
+	oid.T__int2vector:  oid.T_int2vector,
+	oid.T__oidvector:   oid.T_oidvector,
+	oid.T__date:        oid.T_date,
+	oid.T__time:        oid.T_time,
+	oid.T__timetz:      oid.T_timetz,
+	oid.T__timestamp:   oid.T_timestamp,
+	oid.T__timestamptz: oid.T_timestamptz,
+	oid.T__interval:    oid.T_interval,
+	oid.T__aclitem:     oid.T_aclitem,
+	oid.T__bytea:       oid.T_bytea,
+}
+
+// isArrayParam reports whether v is a slice that encode renders as an
+// array. Byte slices are not: they are sent as-is, or as bytea.
+func isArrayParam(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8
+}
+
+// encodeArray renders the slice rv as the text representation of an array
+// of type typ. Nested slices become additional dimensions, and nil
+// elements become NULL.
+func encodeArray(ps *parameterStatus, rv reflect.Value, typ oid.Oid) ([]byte, error) {
+	return appendArray(ps, nil, rv, arrayTypes[typ])
+}
+
+func appendArray(ps *parameterStatus, b []byte, rv reflect.Value, elem oid.Oid) ([]byte, error) {
+	b = append(b, '{')
+	for i := 0; i < rv.Len(); i++ {
+		if i > 0 {
+			b = append(b, ',')
+		}
+
+		e := rv.Index(i)
+		for (e.Kind() == reflect.Interface || e.Kind() == reflect.Ptr) && !e.IsNil() {
+			e = e.Elem()
+		}
+
+		switch {
+		case e.Kind() == reflect.Interface || e.Kind() == reflect.Ptr:
+			// only nil interfaces and pointers are left
+			b = append(b, "NULL"...)
+		case e.Kind() == reflect.Slice && e.IsNil():
+			b = append(b, "NULL"...)
+		case e.Kind() == reflect.Slice && e.Type().Elem().Kind() != reflect.Uint8:
+			var err error
+			if b, err = appendArray(ps, b, e, elem); err != nil {
+				return nil, err
+			}
+		default:
+			v, err := encode(ps, e.Interface(), elem)
+			if err != nil {
+				return nil, err
+			}
+			if v == nil {
+				b = append(b, "NULL"...)
+			} else {
+				b = appendArrayElement(b, v)
+			}
+		}
+	}
+	return append(b, '}'), nil
+}
+
+// appendArrayElement appends the encoded element v, quoting and escaping
+// it if it would otherwise be misread.
+func appendArrayElement(b, v []byte) []byte {
+	if !arrayElementNeedsQuotes(v) {
+		return append(b, v...)
+	}
+
+	b = append(b, '"')
+	for _, c := range v {
+		if c == '"' || c == '\\' {
+			b = append(b, '\\')
+		}
+		b = append(b, c)
+	}
+	return append(b, '"')
+}
+
+func arrayElementNeedsQuotes(v []byte) bool {
+	if len(v) == 0 || bytes.EqualFold(v, []byte("NULL")) {
+		return true
+	}
+	for _, c := range v {
+		switch c {
+		case '{', '}', ',', '"', '\\':
+			return true
+		}
+		if isArraySpace(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// Array returns a value that binds a slice as an array parameter, or scans
+// an array into the slice pointed to by a. It is meant for []bool,
+// []float64, []int64 and []string, but any slice of values that can be
+// passed as query parameters will do; nested slices are multi-dimensional
+// arrays. For example:
+//
+//	db.Query("SELECT * FROM t WHERE id = ANY($1)", pq.Array([]int64{235, 401}))
+//
+//	var names []string
+//	err := db.QueryRow("SELECT names FROM t").Scan(pq.Array(&names))
+//
+// A [][]byte binds as a bytea[]; scan a bytea[] into a ByteaArray. Array
+// columns are decoded as text, so that they can be scanned into a string;
+// scanning them into a slice parses the text of each element, which also
+// works for []time.Time. A NULL array scans into a nil slice, and NULL
+// elements can only be stored in slices of pointers or interfaces, or of
+// types whose pointers are sql.Scanners, such as []sql.NullBool. Elements
+// scanned into interfaces are strings.
+func Array(a interface{}) interface {
+	driver.Valuer
+	sql.Scanner
+} {
+	return genericArray{a}
+}
+
+type genericArray struct {
+	a interface{}
+}
+
+// Value implements the driver Valuer interface.
+func (a genericArray) Value() (driver.Value, error) {
+	rv := reflect.ValueOf(a.a)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("pq: cannot convert %T to array", a.a)
+	}
+	if rv.IsNil() {
+		return nil, nil
+	}
+	// Without the parameter's type to go by, a [][]byte is taken to be a
+	// bytea[], rather than an array of text.
+	var typ oid.Oid
+	if rv.Type().Elem() == bytesType {
+		typ = oid.T__bytea
+	}
+	b, err := encodeArray(&parameterStatus{}, rv, typ)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements the Scanner interface.
+func (a genericArray) Scan(src interface{}) error {
+	dv := reflect.ValueOf(a.a)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() || dv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("pq: cannot scan array into %T; a pointer to a slice is required", a.a)
+	}
+	dv = dv.Elem()
+
+	switch src := src.(type) {
+	case nil:
+		dv.Set(reflect.Zero(dv.Type()))
+		return nil
+	case []byte:
+		return a.scanText(dv, src)
+	case string:
+		return a.scanText(dv, []byte(src))
+	}
+	return fmt.Errorf("pq: cannot scan %T into %T", src, a.a)
+}
+
+func (a genericArray) scanText(dv reflect.Value, src []byte) error {
+	elems, err := parseArray(src, ',')
+	if err != nil {
+		return err
+	}
+	return setArray(dv, elems)
+}
+
+// StringArray represents a one-dimensional array of a text type, such as
+// text[] or varchar[], without NULL elements. A nil StringArray binds as
+// NULL, and an empty one as an empty array.
+type StringArray []string
+
+// Scan implements the Scanner interface.
+func (a *StringArray) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		return a.scanText(src)
+	case string:
+		return a.scanText([]byte(src))
+	}
+	return fmt.Errorf("pq: cannot scan %T into StringArray", src)
+}
+
+func (a *StringArray) scanText(src []byte) error {
+	elems, err := parseArray(src, ',')
+	if err != nil {
+		return err
+	}
+	s := make(StringArray, len(elems))
+	for i, e := range elems {
+		switch e := e.(type) {
+		case []byte:
+			s[i] = string(e)
+		case nil:
+			return fmt.Errorf("pq: cannot scan a NULL element into StringArray")
+		default:
+			return fmt.Errorf("pq: cannot scan a multi-dimensional array into StringArray")
+		}
+	}
+	*a = s
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (a StringArray) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	b := []byte{'{'}
+	for i, s := range a {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendArrayElement(b, []byte(s))
+	}
+	return string(append(b, '}')), nil
+}
+
+// Int64Array represents a one-dimensional array of an integer type, such
+// as int4[] or int8[], without NULL elements. A nil Int64Array binds as
+// NULL, and an empty one as an empty array.
+type Int64Array []int64
+
+// Scan implements the Scanner interface.
+func (a *Int64Array) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		return a.scanText(src)
+	case string:
+		return a.scanText([]byte(src))
+	}
+	return fmt.Errorf("pq: cannot scan %T into Int64Array", src)
+}
+
+func (a *Int64Array) scanText(src []byte) error {
+	elems, err := parseArray(src, ',')
+	if err != nil {
+		return err
+	}
+	s := make(Int64Array, len(elems))
+	for i, e := range elems {
+		b, ok := e.([]byte)
+		if !ok {
+			return numericArrayElemError(e, "Int64Array")
+		}
+		if s[i], err = strconv.ParseInt(string(b), 10, 64); err != nil {
+			return fmt.Errorf("pq: cannot scan array element %q into Int64Array: %s", b, err)
+		}
+	}
+	*a = s
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (a Int64Array) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	b := []byte{'{'}
+	for i, n := range a {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = strconv.AppendInt(b, n, 10)
+	}
+	return string(append(b, '}')), nil
+}
+
+// Float64Array represents a one-dimensional array of a floating-point
+// type, such as float8[], without NULL elements. A nil Float64Array binds
+// as NULL, and an empty one as an empty array.
+type Float64Array []float64
+
+// Scan implements the Scanner interface.
+func (a *Float64Array) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		return a.scanText(src)
+	case string:
+		return a.scanText([]byte(src))
+	}
+	return fmt.Errorf("pq: cannot scan %T into Float64Array", src)
+}
+
+func (a *Float64Array) scanText(src []byte) error {
+	elems, err := parseArray(src, ',')
+	if err != nil {
+		return err
+	}
+	s := make(Float64Array, len(elems))
+	for i, e := range elems {
+		b, ok := e.([]byte)
+		if !ok {
+			return numericArrayElemError(e, "Float64Array")
+		}
+		if s[i], err = strconv.ParseFloat(string(b), 64); err != nil {
+			return fmt.Errorf("pq: cannot scan array element %q into Float64Array: %s", b, err)
+		}
+	}
+	*a = s
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (a Float64Array) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	b := []byte{'{'}
+	for i, f := range a {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = append(b, encodeFloat(&parameterStatus{}, f, 64)...)
+	}
+	return string(append(b, '}')), nil
+}
+
+// ByteaArray represents a one-dimensional bytea[], in which NULL elements
+// are nil. bytea[] columns are decoded as text, so that they can be
+// scanned into a string; scan them into a ByteaArray to parse them. A nil
+// ByteaArray binds as NULL, and an empty one as an empty array.
+type ByteaArray [][]byte
+
+// Scan implements the Scanner interface.
+func (a *ByteaArray) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		return a.scanText(src)
+	case string:
+		return a.scanText([]byte(src))
+	}
+	return fmt.Errorf("pq: cannot scan %T into ByteaArray", src)
+}
+
+func (a *ByteaArray) scanText(src []byte) error {
+	elems, err := parseArray(src, ',')
+	if err != nil {
+		return err
+	}
+	s := make(ByteaArray, len(elems))
+	for i, e := range elems {
+		switch e := e.(type) {
+		case []byte:
+			if s[i], err = parseBytea(e); err != nil {
+				return err
+			}
+		case nil:
+		default:
+			return fmt.Errorf("pq: cannot scan a multi-dimensional array into ByteaArray")
+		}
+	}
+	*a = s
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (a ByteaArray) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	b, err := encodeArray(&parameterStatus{}, reflect.ValueOf([][]byte(a)), oid.T__bytea)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// numericArrayElemError returns the error for the array element e, which
+// is NULL or a nested array, and so cannot be scanned into the numeric
+// array type name.
+func numericArrayElemError(e interface{}, name string) error {
+	if e == nil {
+		return fmt.Errorf("pq: cannot scan a NULL element into %s", name)
+	}
+	return fmt.Errorf("pq: cannot scan a multi-dimensional array into %s", name)
+}
+
+// setArray stores the elements of a, as returned by parseArray, into the
+// slice dv.
+func setArray(dv reflect.Value, a []interface{}) error {
+	s := reflect.MakeSlice(dv.Type(), len(a), len(a))
+	for i, e := range a {
+		var err error
+		if sub, ok := e.([]interface{}); ok {
+			if s.Index(i).Kind() != reflect.Slice {
+				return fmt.Errorf("pq: cannot scan multi-dimensional array into %s", dv.Type())
+			}
+			err = setArray(s.Index(i), sub)
+		} else {
+			err = setArrayElem(s.Index(i), e)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	dv.Set(s)
+	return nil
+}
+
+// setArrayElem stores the element e, which is the text of an array element
+// or composite field, or nil for NULL, into dv.
+func setArrayElem(dv reflect.Value, e interface{}) error {
+	if dv.Kind() != reflect.Ptr && dv.CanAddr() {
+		if s, ok := dv.Addr().Interface().(sql.Scanner); ok {
+			return s.Scan(e)
+		}
+	}
+
+	if e == nil {
+		switch dv.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+			dv.Set(reflect.Zero(dv.Type()))
+			return nil
+		}
+		return fmt.Errorf("pq: cannot scan NULL array element into %s", dv.Type())
+	}
+
+	var text []byte
+	switch e := e.(type) {
+	case []byte:
+		text = e
+	case string:
+		text = []byte(e)
+	default:
+		return fmt.Errorf("pq: cannot scan array element of type %T into %s", e, dv.Type())
+	}
+
+	switch kind := dv.Kind(); {
+	case kind == reflect.Interface:
+		// Without the element type to go by, the text is kept as it is.
+		dv.Set(reflect.ValueOf(string(text)))
+		return nil
+	case kind == reflect.Ptr:
+		p := reflect.New(dv.Type().Elem())
+		if err := setArrayElem(p.Elem(), e); err != nil {
+			return err
+		}
+		dv.Set(p)
+		return nil
+	case dv.Type() == timeType:
+		t, err := parseTs(time.Local, string(text))
+		if err != nil {
+			return fmt.Errorf("pq: cannot scan array element %q into %s: %s", text, dv.Type(), err)
+		}
+		dv.Set(reflect.ValueOf(t))
+		return nil
+	case kind == reflect.Slice && dv.Type().Elem().Kind() == reflect.Uint8:
+		dv.SetBytes(append([]byte(nil), text...))
+		return nil
+	case kind == reflect.String:
+		dv.SetString(string(text))
+		return nil
+	case kind == reflect.Bool:
+		b, err := parseBool(text)
+		if err != nil {
+			return fmt.Errorf("pq: cannot scan array element %q into %s: %s", text, dv.Type(), err)
+		}
+		dv.SetBool(b)
+		return nil
+	case kind >= reflect.Int && kind <= reflect.Int64:
+		i, err := strconv.ParseInt(string(text), 10, dv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("pq: cannot scan array element %q into %s: %s", text, dv.Type(), err)
+		}
+		dv.SetInt(i)
+		return nil
+	case kind == reflect.Float32 || kind == reflect.Float64:
+		f, err := strconv.ParseFloat(string(text), dv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("pq: cannot scan array element %q into %s: %s", text, dv.Type(), err)
+		}
+		dv.SetFloat(f)
+		return nil
+	}
+	return fmt.Errorf("pq: cannot scan array element into %s", dv.Type())
+}
+
+// parseArray parses the text representation of an array into nested
+// []interface{} values, one level per dimension. Leaves hold the unescaped
+// text of each element as a []byte, or nil for NULL elements. del is the
+// element delimiter, which is ',' for all but a handful of types.
+func parseArray(src []byte, del byte) ([]interface{}, error) {
+	p := arrayParser{src: src, del: del}
+
+	// Arrays whose lower bounds are not 1 are prefixed with their
+	// dimensions, e.g. "[0:1]={1,2}".
+	if len(src) > 0 && src[0] == '[' {
+		i := bytes.IndexByte(src, '=')
+		if i < 0 {
+			return nil, p.error("expected '=' after array dimensions")
+		}
+		p.pos = i + 1
+	}
+
+	a, err := p.array()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos != len(src) {
+		return nil, p.error("unexpected trailing data")
+	}
+	return a, nil
+}
+
+type arrayParser struct {
+	src []byte
+	pos int
+	del byte
+}
+
+func (p *arrayParser) error(msg string) error {
+	return fmt.Errorf("pq: unable to parse array %q at offset %d: %s", p.src, p.pos, msg)
+}
+
+// peek returns the next byte of input, or 0 at the end of input.
+func (p *arrayParser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+func (p *arrayParser) skipSpace() {
+	for p.pos < len(p.src) && isArraySpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *arrayParser) array() ([]interface{}, error) {
+	p.skipSpace()
+	if p.peek() != '{' {
+		return nil, p.error("expected '{'")
+	}
+	p.pos++
+
+	a := []interface{}{}
+	p.skipSpace()
+	if p.peek() == '}' {
+		p.pos++
+		return a, nil
+	}
+
+	for {
+		var e interface{}
+		var err error
+
+		p.skipSpace()
+		switch p.peek() {
+		case '{':
+			e, err = p.array()
+		case '"':
+			e, err = p.quoted()
+		default:
+			e, err = p.unquoted()
+		}
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, e)
+
+		p.skipSpace()
+		switch p.peek() {
+		case p.del:
+			p.pos++
+		case '}':
+			p.pos++
+			return a, nil
+		default:
+			return nil, p.error(fmt.Sprintf("expected %q or '}'", p.del))
+		}
+	}
+}
+
+// quoted reads a double-quoted element, which is never NULL.
+func (p *arrayParser) quoted() (interface{}, error) {
+	p.pos++ // opening quote
+	e := []byte{}
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		p.pos++
+		switch c {
+		case '"':
+			return e, nil
+		case '\\':
+			if p.pos == len(p.src) {
+				return nil, p.error("unterminated escape")
+			}
+			c = p.src[p.pos]
+			p.pos++
+		}
+		e = append(e, c)
+	}
+	return nil, p.error("unterminated quoted element")
+}
+
+// unquoted reads an element that is not double-quoted. Surrounding
+// whitespace is not part of the element, and the bare token NULL denotes
+// a NULL element.
+func (p *arrayParser) unquoted() (interface{}, error) {
+	var e []byte
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == p.del || c == '}' {
+			break
+		}
+		switch c {
+		case '{', '"':
+			return nil, p.error(fmt.Sprintf("unexpected %q", c))
+		case '\\':
+			p.pos++
+			if p.pos == len(p.src) {
+				return nil, p.error("unterminated escape")
+			}
+			c = p.src[p.pos]
+		}
+		e = append(e, c)
+		p.pos++
+	}
+
+	e = bytes.TrimRight(e, " \t\n\r\v\f")
+	if len(e) == 0 {
+		return nil, p.error("empty element")
+	}
+	if bytes.EqualFold(e, []byte("NULL")) {
+		return nil, nil
+	}
+	return e, nil
+}
+
+func isArraySpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', '\v', '\f':
+		return true
+	}
+	return false
+}
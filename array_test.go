@@ -0,0 +1,748 @@
+package pq
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"github.com/lib/pq/oid"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseArray(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  []interface{}
+	}{
+		{`{}`, []interface{}{}},
+		{`{1}`, []interface{}{[]byte("1")}},
+		{`{1,2,3}`, []interface{}{[]byte("1"), []byte("2"), []byte("3")}},
+		{`{NULL,null,"NULL"}`, []interface{}{nil, nil, []byte("NULL")}},
+		{`{"a,b","{c}","d\"e","f\\g",""}`, []interface{}{
+			[]byte("a,b"), []byte("{c}"), []byte(`d"e`), []byte(`f\g`), []byte(""),
+		}},
+		{`{{1,2},{3,4}}`, []interface{}{
+			[]interface{}{[]byte("1"), []byte("2")},
+			[]interface{}{[]byte("3"), []byte("4")},
+		}},
+		{`[0:1]={5,6}`, []interface{}{[]byte("5"), []byte("6")}},
+		{`{ a , b }`, []interface{}{[]byte("a"), []byte("b")}},
+	} {
+		got, err := parseArray([]byte(tt.input), ',')
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: expected %#v, got %#v", tt.input, tt.want, got)
+		}
+	}
+}
+
+func TestParseArrayError(t *testing.T) {
+	for _, input := range []string{
+		``,
+		`1,2`,
+		`{1,2`,
+		`{1,,2}`,
+		`{"abc}`,
+		`{1}x`,
+		`[1:2]{1,2}`,
+	} {
+		_, err := parseArray([]byte(input), ',')
+		if err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestDecodeArray(t *testing.T) {
+	for _, typ := range []oid.Oid{oid.T__int4, oid.T__float8, oid.T__bool, oid.T__text, oid.T__timestamptz, oid.T__bytea} {
+		input := []byte(`{1,NULL,"a,b"}`)
+		got, ok := mustDecode(t, &parameterStatus{}, input, typ, formatText).([]byte)
+		if !ok || string(got) != string(input) {
+			t.Errorf("%v: expected the text %q, got %#v", typ, input, got)
+		}
+	}
+}
+
+func TestArrayScanTypes(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		dest  interface{}
+		want  interface{}
+	}{
+		{`{1,2,3}`, &[]int64{}, &[]int64{1, 2, 3}},
+		{`{}`, &[]int64{}, &[]int64{}},
+		{`{{1,2},{3,4}}`, &[][]int16{}, &[][]int16{{1, 2}, {3, 4}}},
+		{`{1.5,-2}`, &[]float64{}, &[]float64{1.5, -2}},
+		{`{t,f}`, &[]bool{}, &[]bool{true, false}},
+		{`{foo,"bar,baz"}`, &[]string{}, &[]string{"foo", "bar,baz"}},
+		{`{1.50,-2,NaN}`, &[]string{}, &[]string{"1.50", "-2", "NaN"}},
+		{`{p,x,""}`, &[]string{}, &[]string{"p", "x", ""}},
+		{`{"1 0","",2}`, &[]string{}, &[]string{"1 0", "", "2"}},
+		{`{1,NULL}`, &[]interface{}{}, &[]interface{}{"1", nil}},
+		{`{{a,NULL},{b,c}}`, &[][]interface{}{}, &[][]interface{}{{"a", nil}, {"b", "c"}}},
+	} {
+		if err := Array(tt.dest).Scan([]byte(tt.input)); err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(tt.dest, tt.want) {
+			t.Errorf("%q: expected %#v, got %#v", tt.input, tt.want, tt.dest)
+		}
+	}
+}
+
+func TestTimestamptzArrayScanner(t *testing.T) {
+	in := `{"2012-11-06 10:23:42.5+05:30","2012-11-06 10:23:42-08",NULL,"0044-03-15 12:00:00+00 BC"}`
+	var got []*time.Time
+	if err := Array(&got).Scan([]byte(in)); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 4 {
+		t.Fatalf("expected 4 elements, got %#v", got)
+	}
+	for i, want := range []time.Time{
+		time.Date(2012, 11, 6, 10, 23, 42, 5e8, time.FixedZone("", 5*60*60+30*60)),
+		time.Date(2012, 11, 6, 10, 23, 42, 0, time.FixedZone("", -8*60*60)),
+		{},
+		time.Date(-43, 3, 15, 12, 0, 0, 0, time.UTC),
+	} {
+		if want.IsZero() {
+			if got[i] != nil {
+				t.Errorf("%d: expected nil, got %v", i, got[i])
+			}
+			continue
+		}
+		if got[i] == nil || !got[i].Equal(want) {
+			t.Errorf("%d: expected %v, got %v", i, want, got[i])
+			continue
+		}
+		_, off := got[i].Zone()
+		if _, wantOff := want.Zone(); off != wantOff {
+			t.Errorf("%d: expected offset %d, got %d", i, wantOff, off)
+		}
+	}
+}
+
+func TestTimestamptzArrayScan(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var ts []time.Time
+	err := db.QueryRow(`SELECT ARRAY['2012-11-06 10:23:42.5+05:30', '0044-03-15 12:00:00+00 BC']::timestamptz[]`).Scan(Array(&ts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Time{
+		time.Date(2012, 11, 6, 4, 53, 42, 5e8, time.UTC),
+		time.Date(-43, 3, 15, 12, 0, 0, 0, time.UTC),
+	}
+	if len(ts) != len(want) {
+		t.Fatalf("expected %v, got %v", want, ts)
+	}
+	for i := range want {
+		if !ts[i].Equal(want[i]) {
+			t.Errorf("%d: expected %v, got %v", i, want[i], ts[i])
+		}
+	}
+}
+
+func TestIntervalAndNumericArrayScan(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var ivs []Interval
+	var nums []string
+	err := db.QueryRow("SELECT ARRAY['1 day','2 days']::interval[], ARRAY[1.50, -2]::numeric[]").Scan(Array(&ivs), Array(&nums))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Interval{{Days: 1}, {Days: 2}}; !reflect.DeepEqual(ivs, want) {
+		t.Errorf("expected %v, got %v", want, ivs)
+	}
+	if want := []string{"1.50", "-2"}; !reflect.DeepEqual(nums, want) {
+		t.Errorf("expected %v, got %v", want, nums)
+	}
+}
+
+// trickyArrayStrings are text array elements that need quoting or escaping.
+var trickyArrayStrings = []string{
+	"", " ", " lead", "trail ", "in side", "a,b", "{x}", "}", `"`, `q"q`,
+	`\`, `a\b`, `\"`, "NULL", "null", "Null", "\t", "line\nbreak", "é", "'",
+}
+
+func TestDecodeTextArrayEscaping(t *testing.T) {
+	input := `{"",NULL,"NULL","null"," lead","trail ","a,b","{x}","q\"q","a\\b",plain,é}`
+	want := []interface{}{"", nil, "NULL", "null", " lead", "trail ", "a,b", "{x}", `q"q`, `a\b`, "plain", "é"}
+	var got []interface{}
+	if err := Array(&got).Scan([]byte(input)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %#v, got %#v", want, got)
+	}
+
+	for _, typ := range []oid.Oid{oid.T__text, oid.T__varchar} {
+		enc := mustEncode(t, trickyArrayStrings, typ)
+		var got []string
+		if err := Array(&got).Scan(enc); err != nil {
+			t.Fatalf("%v: %s: %v", typ, enc, err)
+		}
+		if !reflect.DeepEqual(got, trickyArrayStrings) {
+			t.Errorf("%v: %s scanned as %#v", typ, enc, got)
+		}
+	}
+}
+
+func TestTextArrayEscaping(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var got []string
+	err := db.QueryRow("SELECT $1::text[]", trickyArrayStrings).Scan(Array(&got))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, trickyArrayStrings) {
+		t.Errorf("expected %#v, got %#v", trickyArrayStrings, got)
+	}
+
+	var n int
+	var nulls []interface{}
+	err = db.QueryRow(`SELECT array_length($1::text[], 1), ARRAY[NULL, 'NULL']::varchar[]`,
+		[]string{"NULL", "null"}).Scan(&n, Array(&nulls))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("expected 2 elements, got %d", n)
+	}
+	if want := []interface{}{nil, "NULL"}; !reflect.DeepEqual(nulls, want) {
+		t.Errorf("expected %#v, got %#v", want, nulls)
+	}
+}
+
+func TestArrayScan(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var ints []int64
+	err := db.QueryRow("SELECT '{1,2,3}'::int[]").Scan(Array(&ints))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ints, []int64{1, 2, 3}) {
+		t.Errorf("expected [1 2 3], got %v", ints)
+	}
+
+	var strs []string
+	err = db.QueryRow(`SELECT ARRAY['a', 'b,c', 'd"e']`).Scan(Array(&strs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(strs, []string{"a", "b,c", `d"e`}) {
+		t.Errorf(`expected [a b,c d"e], got %v`, strs)
+	}
+}
+
+func TestEncodeArray(t *testing.T) {
+	s := "foo"
+	for _, tt := range []struct {
+		input interface{}
+		want  string
+	}{
+		{[]int64{}, `{}`},
+		{[]int64{1, 2, 3}, `{1,2,3}`},
+		{[][]int64{{1, 2}, {3, 4}}, `{{1,2},{3,4}}`},
+		{[]bool{true, false}, `{true,false}`},
+		{[]string{"a", "b,c", `d"e`, `f\g`, "{h}", "", "NULL", "i j"},
+			`{a,"b,c","d\"e","f\\g","{h}","","NULL","i j"}`},
+		{[]*string{&s, nil}, `{foo,NULL}`},
+		{[]interface{}{int64(1), nil, "x"}, `{1,NULL,x}`},
+	} {
+		got := string(mustEncode(t, tt.input, oid.T_unknown))
+		if got != tt.want {
+			t.Errorf("%#v: expected %s, got %s", tt.input, tt.want, got)
+		}
+	}
+}
+
+func TestArrayParameter(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var ok bool
+	err := db.QueryRow("SELECT $1::int8[] = '{1,2,3}'", []int64{1, 2, 3}).Scan(&ok)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Error("expected the int8[] parameter to round-trip")
+	}
+
+	err = db.QueryRow(`SELECT $1::text[] = ARRAY['a,b', 'c"d', NULL]`,
+		[]interface{}{"a,b", `c"d`, nil}).Scan(&ok)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Error("expected the text[] parameter to round-trip")
+	}
+}
+
+func TestArrayValue(t *testing.T) {
+	for _, tt := range []struct {
+		input interface{}
+		want  driver.Value
+	}{
+		{[]int64{1, 2}, "{1,2}"},
+		{[]float64{1.5}, "{1.5}"},
+		{[]string{"a", "b c"}, `{a,"b c"}`},
+		{[]bool{}, "{}"},
+		{[]int64(nil), nil},
+		{(*[]string)(nil), nil},
+		{&[]bool{true}, "{true}"},
+	} {
+		got, err := Array(tt.input).Value()
+		if err != nil {
+			t.Errorf("%#v: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%#v: expected %#v, got %#v", tt.input, tt.want, got)
+		}
+	}
+
+	if _, err := Array(1).Value(); err == nil {
+		t.Error("expected an error for a non-slice")
+	}
+}
+
+func TestArrayScanner(t *testing.T) {
+	var ints []int64
+	for _, tt := range []struct {
+		src  interface{}
+		want []int64
+	}{
+		{nil, nil},
+		{[]byte("{}"), []int64{}},
+		{[]byte("{7}"), []int64{7}},
+		{"{1,2,3}", []int64{1, 2, 3}},
+	} {
+		if err := Array(&ints).Scan(tt.src); err != nil {
+			t.Errorf("%#v: unexpected error: %v", tt.src, err)
+			continue
+		}
+		if !reflect.DeepEqual(ints, tt.want) {
+			t.Errorf("%#v: expected %#v, got %#v", tt.src, tt.want, ints)
+		}
+	}
+
+	var strs []string
+	if err := Array(&strs).Scan([]byte(`{a,"b,c",""}`)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(strs, []string{"a", "b,c", ""}) {
+		t.Errorf("unexpected result: %#v", strs)
+	}
+
+	var bools [][]bool
+	if err := Array(&bools).Scan([]byte(`{{t,f},{f,t}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(bools, [][]bool{{true, false}, {false, true}}) {
+		t.Errorf("unexpected result: %#v", bools)
+	}
+
+	var ptrs []*float64
+	if err := Array(&ptrs).Scan([]byte("{1.5,NULL}")); err != nil {
+		t.Fatal(err)
+	}
+	if len(ptrs) != 2 || *ptrs[0] != 1.5 || ptrs[1] != nil {
+		t.Errorf("unexpected result: %#v", ptrs)
+	}
+
+	for _, src := range []interface{}{
+		[]byte("{1,NULL}"),
+		[]byte("{1,x}"),
+		[]byte("{{1}}"),
+		int64(1),
+	} {
+		if err := Array(&ints).Scan(src); err == nil {
+			t.Errorf("%#v: expected an error", src)
+		}
+	}
+
+	if err := Array(ints).Scan([]byte("{}")); err == nil {
+		t.Error("expected an error scanning into a non-pointer")
+	}
+}
+
+func TestNullBoolArrayScanner(t *testing.T) {
+	yes, no, null := sql.NullBool{Bool: true, Valid: true}, sql.NullBool{Valid: true}, sql.NullBool{}
+	for _, tt := range []struct {
+		src  interface{}
+		want []sql.NullBool
+	}{
+		{[]byte("{}"), []sql.NullBool{}},
+		{[]byte("{t,f,NULL,TRUE}"), []sql.NullBool{yes, no, null, yes}},
+		{[]byte("{NULL,NULL}"), []sql.NullBool{null, null}},
+	} {
+		var bools []sql.NullBool
+		if err := Array(&bools).Scan(tt.src); err != nil {
+			t.Errorf("%#v: unexpected error: %v", tt.src, err)
+			continue
+		}
+		if !reflect.DeepEqual(bools, tt.want) {
+			t.Errorf("%#v: expected %#v, got %#v", tt.src, tt.want, bools)
+		}
+	}
+
+	var bools []sql.NullBool
+	if err := Array(&bools).Scan([]byte(`{t,"NULL"}`)); err == nil {
+		t.Error("expected an error for a quoted NULL")
+	}
+
+	var plain []bool
+	if err := Array(&plain).Scan([]byte("{yes,OFF, 1 }")); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(plain, []bool{true, false, true}) {
+		t.Errorf("unexpected result: %#v", plain)
+	}
+}
+
+func TestNullBoolArrayScan(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	yes, no, null := sql.NullBool{Bool: true, Valid: true}, sql.NullBool{Valid: true}, sql.NullBool{}
+	for _, tt := range []struct {
+		query string
+		want  []sql.NullBool
+	}{
+		{"SELECT '{t,f,NULL,t}'::bool[]", []sql.NullBool{yes, no, null, yes}},
+		{"SELECT '{NULL,NULL}'::bool[]", []sql.NullBool{null, null}},
+		{"SELECT '{}'::bool[]", []sql.NullBool{}},
+	} {
+		var bools []sql.NullBool
+		if err := db.QueryRow(tt.query).Scan(Array(&bools)); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(bools, tt.want) {
+			t.Errorf("%s: expected %#v, got %#v", tt.query, tt.want, bools)
+		}
+	}
+}
+
+func TestCatalogArrayScan(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var chars []string
+	var vectors []Int2Vector
+	err := db.QueryRow(`SELECT ARRAY['p', 'x']::"char"[], ARRAY['1 0'::int2vector, '2'::int2vector]`).Scan(Array(&chars), Array(&vectors))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(chars, []string{"p", "x"}) {
+		t.Errorf("unexpected chars %#v", chars)
+	}
+	if !reflect.DeepEqual(vectors, []Int2Vector{{1, 0}, {2}}) {
+		t.Errorf("unexpected vectors %#v", vectors)
+	}
+}
+
+func TestStringArrayValue(t *testing.T) {
+	for _, tt := range []struct {
+		a    StringArray
+		want driver.Value
+	}{
+		{nil, nil},
+		{StringArray{}, "{}"},
+		{StringArray{"a", "b"}, "{a,b}"},
+		{StringArray{"", "NULL", "null"}, `{"","NULL","null"}`},
+		{StringArray{`say "hi"`, "a,b", `back\slash`, "{x}", " space"}, `{"say \"hi\"","a,b","back\\slash","{x}"," space"}`},
+	} {
+		got, err := tt.a.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%#v: expected %#v, got %#v", tt.a, tt.want, got)
+		}
+	}
+}
+
+func TestStringArrayScan(t *testing.T) {
+	for _, tt := range []struct {
+		src  interface{}
+		want StringArray
+	}{
+		{nil, nil},
+		{[]byte("{}"), StringArray{}},
+		{"{a,b}", StringArray{"a", "b"}},
+		{[]byte(`{"","NULL","a,b","say \"hi\"","back\\slash"}`), StringArray{"", "NULL", "a,b", `say "hi"`, `back\slash`}},
+	} {
+		a := StringArray{"old"}
+		if err := a.Scan(tt.src); err != nil {
+			t.Errorf("%#v: unexpected error: %v", tt.src, err)
+			continue
+		}
+		if !reflect.DeepEqual(a, tt.want) {
+			t.Errorf("%#v: expected %#v, got %#v", tt.src, tt.want, a)
+		}
+	}
+
+	for _, src := range []interface{}{
+		[]byte("{a,NULL}"),
+		[]byte("{{a},{b}}"),
+		[]byte("{a"),
+		[]string{"a"},
+		int64(1),
+	} {
+		var a StringArray
+		if err := a.Scan(src); err == nil {
+			t.Errorf("%#v: expected an error", src)
+		}
+	}
+}
+
+func TestStringArrayRoundTrip(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	for _, in := range []StringArray{
+		nil,
+		{},
+		{"a", "", "NULL", `say "hi"`, "a,b", `back\slash`, "{x}", " space "},
+	} {
+		var out StringArray
+		err := db.QueryRow("SELECT $1::text[]", in).Scan(&out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(out, in) {
+			t.Errorf("expected %#v, got %#v", in, out)
+		}
+	}
+}
+
+func TestInt64Array(t *testing.T) {
+	for _, tt := range []struct {
+		a    Int64Array
+		want driver.Value
+	}{
+		{nil, nil},
+		{Int64Array{}, "{}"},
+		{Int64Array{1, -2, 9223372036854775807}, "{1,-2,9223372036854775807}"},
+	} {
+		got, err := tt.a.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%#v: expected %#v, got %#v", tt.a, tt.want, got)
+		}
+	}
+
+	for _, tt := range []struct {
+		src  interface{}
+		want Int64Array
+	}{
+		{nil, nil},
+		{[]byte("{}"), Int64Array{}},
+		{"{1, -2 ,3}", Int64Array{1, -2, 3}},
+	} {
+		a := Int64Array{99}
+		if err := a.Scan(tt.src); err != nil {
+			t.Errorf("%#v: unexpected error: %v", tt.src, err)
+			continue
+		}
+		if !reflect.DeepEqual(a, tt.want) {
+			t.Errorf("%#v: expected %#v, got %#v", tt.src, tt.want, a)
+		}
+	}
+
+	for _, src := range []interface{}{
+		[]byte("{1,NULL}"),
+		[]byte("{1,x}"),
+		[]byte("{1.5}"),
+		[]byte("{9223372036854775808}"),
+		[]byte("{{1}}"),
+		[]int64{1},
+		1.5,
+	} {
+		var a Int64Array
+		if err := a.Scan(src); err == nil {
+			t.Errorf("%#v: expected an error", src)
+		}
+	}
+}
+
+func TestFloat64Array(t *testing.T) {
+	for _, tt := range []struct {
+		a    Float64Array
+		want driver.Value
+	}{
+		{nil, nil},
+		{Float64Array{}, "{}"},
+		{Float64Array{1.5, -2, 1e100}, "{1.5,-2,1e+100}"},
+		{Float64Array{math.NaN(), math.Inf(1), math.Inf(-1)}, "{NaN,Infinity,-Infinity}"},
+	} {
+		got, err := tt.a.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%#v: expected %#v, got %#v", tt.a, tt.want, got)
+		}
+	}
+
+	for _, tt := range []struct {
+		src  interface{}
+		want Float64Array
+	}{
+		{nil, nil},
+		{[]byte("{}"), Float64Array{}},
+		{"{1.5,-2,1e+100,Infinity,-Infinity}", Float64Array{1.5, -2, 1e100, math.Inf(1), math.Inf(-1)}},
+	} {
+		a := Float64Array{99}
+		if err := a.Scan(tt.src); err != nil {
+			t.Errorf("%#v: unexpected error: %v", tt.src, err)
+			continue
+		}
+		if !reflect.DeepEqual(a, tt.want) {
+			t.Errorf("%#v: expected %#v, got %#v", tt.src, tt.want, a)
+		}
+	}
+
+	for _, src := range []interface{}{
+		[]byte("{1,NULL}"),
+		[]byte("{1,x}"),
+		[]byte("{{1}}"),
+		[]float64{1.5},
+		int64(1),
+	} {
+		var a Float64Array
+		if err := a.Scan(src); err == nil {
+			t.Errorf("%#v: expected an error", src)
+		}
+	}
+}
+
+func TestNumericArrayRoundTrip(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	ints := Int64Array{1, -2, 9223372036854775807}
+	floats := Float64Array{1.5, -2, math.Inf(1)}
+	var outInts Int64Array
+	var outFloats Float64Array
+	err := db.QueryRow("SELECT $1::int8[], $2::float8[]", ints, floats).Scan(&outInts, &outFloats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(outInts, ints) {
+		t.Errorf("expected %#v, got %#v", ints, outInts)
+	}
+	if !reflect.DeepEqual(outFloats, floats) {
+		t.Errorf("expected %#v, got %#v", floats, outFloats)
+	}
+}
+
+func TestEncodeByteaArray(t *testing.T) {
+	want := `{"\\x0001ff","\\x",NULL}`
+	got := string(mustEncode(t, [][]byte{{0, 1, 0xff}, {}, nil}, oid.T__bytea))
+	if got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	v, err := Array([][]byte{{0, 1, 0xff}, {}, nil}).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != want {
+		t.Errorf("expected %s from Array, got %v", want, v)
+	}
+}
+
+func TestByteaArray(t *testing.T) {
+	for _, tt := range []struct {
+		src  interface{}
+		want ByteaArray
+	}{
+		{nil, nil},
+		{[]byte("{}"), ByteaArray{}},
+		{[]byte(`{"\\x0001ff","\\x",NULL}`), ByteaArray{{0, 1, 0xff}, {}, nil}},
+		{`{"\\\\a\\001"}`, ByteaArray{{'\\', 'a', 1}}},
+	} {
+		a := ByteaArray{{99}}
+		if err := a.Scan(tt.src); err != nil {
+			t.Errorf("%#v: unexpected error: %v", tt.src, err)
+			continue
+		}
+		if !reflect.DeepEqual(a, tt.want) {
+			t.Errorf("%#v: expected %#v, got %#v", tt.src, tt.want, a)
+		}
+	}
+
+	for _, src := range []interface{}{
+		[]byte("{{}}"),
+		[]byte(`{"\\xzz"}`),
+		[][]byte{{1}},
+	} {
+		var a ByteaArray
+		if err := a.Scan(src); err == nil {
+			t.Errorf("%#v: expected an error", src)
+		}
+	}
+
+	v, err := ByteaArray{{0, 1, 0xff}, {}, nil}.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"\\x0001ff","\\x",NULL}`; v != want {
+		t.Errorf("expected %s, got %v", want, v)
+	}
+	if v, err := ByteaArray(nil).Value(); err != nil || v != nil {
+		t.Errorf("expected nil, got %#v, %v", v, err)
+	}
+}
+
+func TestByteaArrayRoundTrip(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	in := ByteaArray{{0, 1, 0xff}, {}, []byte(`"quoted" \ {braces},`)}
+	for _, param := range []interface{}{[][]byte(in), Array([][]byte(in)), in} {
+		var out ByteaArray
+		err := db.QueryRow("SELECT $1::bytea[]", param).Scan(&out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(out, in) {
+			t.Errorf("%T: expected %q, got %q", param, in, out)
+		}
+	}
+
+	var withNull ByteaArray
+	err := db.QueryRow(`SELECT ARRAY['\x01'::bytea, NULL, 'a\\b'::bytea]`).Scan(&withNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (ByteaArray{{1}, nil, []byte(`a\b`)}); !reflect.DeepEqual(withNull, want) {
+		t.Errorf("expected %q, got %q", want, withNull)
+	}
+
+	var s string
+	if err := db.QueryRow(`SELECT ARRAY['\x01'::bytea]`).Scan(&s); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"\\x01"}`; s != want {
+		t.Errorf("expected %s, got %s", want, s)
+	}
+}
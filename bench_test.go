@@ -281,13 +281,13 @@ func benchPreparedMockQuery(b *testing.B, c *conn, stmt driver.Stmt) {
 
 func BenchmarkEncodeInt64(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		encode(int64(1234), oid.T_int8)
+		encode(&parameterStatus{}, int64(1234), oid.T_int8)
 	}
 }
 
 func BenchmarkEncodeFloat64(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		encode(3.14159, oid.T_float8)
+		encode(&parameterStatus{}, 3.14159, oid.T_float8)
 	}
 }
 
@@ -295,13 +295,21 @@ var testByteString = []byte("abcdefghijklmnopqrstuvwxyz")
 
 func BenchmarkEncodeBytea(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		encode(testByteString, oid.T_bytea)
+		encode(&parameterStatus{}, testByteString, oid.T_bytea)
+	}
+}
+
+var testLargeByteString = make([]byte, 1<<20)
+
+func BenchmarkEncodeLargeBytea(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		encode(&parameterStatus{}, testLargeByteString, oid.T_bytea)
 	}
 }
 
 func BenchmarkEncodeBool(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		encode(true, oid.T_bool)
+		encode(&parameterStatus{}, true, oid.T_bool)
 	}
 }
 
@@ -309,7 +317,7 @@ var testIntBytes = []byte("1234")
 
 func BenchmarkDecodeInt64(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		decode(testIntBytes, oid.T_int8)
+		decode(&parameterStatus{}, testIntBytes, oid.T_int8, formatText)
 	}
 }
 
@@ -317,7 +325,7 @@ var testFloatBytes = []byte("3.14159")
 
 func BenchmarkDecodeFloat64(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		decode(testFloatBytes, oid.T_float8)
+		decode(&parameterStatus{}, testFloatBytes, oid.T_float8, formatText)
 	}
 }
 
@@ -325,7 +333,7 @@ var testBoolBytes = []byte{'t'}
 
 func BenchmarkDecodeBool(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		decode(testBoolBytes, oid.T_bool)
+		decode(&parameterStatus{}, testBoolBytes, oid.T_bool, formatText)
 	}
 }
 
@@ -337,3 +345,11 @@ func TestDecodeBool(t *testing.T) {
 	}
 	rows.Close()
 }
+
+var testEscapedByteaText = bytes.Repeat([]byte("abcdefgh"), 1<<17)
+
+func BenchmarkParseEscapedBytea(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		parseBytea(testEscapedByteaText)
+	}
+}
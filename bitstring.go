@@ -0,0 +1,66 @@
+package pq
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// BitString represents a Postgres bit or bit varying value, as a string of
+// '0' and '1' characters.
+type BitString string
+
+// parseBitString checks that s is made up only of '0' and '1' characters.
+func parseBitString(s string) (BitString, error) {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '0' && s[i] != '1' {
+			return "", fmt.Errorf("pq: unable to parse bit string %q", s)
+		}
+	}
+	return BitString(s), nil
+}
+
+// Len returns the number of bits in b.
+func (b BitString) Len() int {
+	return len(b)
+}
+
+// Bytes returns the bits of b packed into bytes, most significant bit
+// first, as Postgres stores them. If the length of b is not a multiple of
+// eight, the last byte is padded with zero bits.
+func (b BitString) Bytes() []byte {
+	buf := make([]byte, (len(b)+7)/8)
+	for i := 0; i < len(b); i++ {
+		if b[i] == '1' {
+			buf[i/8] |= 0x80 >> uint(i%8)
+		}
+	}
+	return buf
+}
+
+// Scan implements the Scanner interface.
+func (b *BitString) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case BitString:
+		*b = v
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("pq: cannot scan %T into BitString", value)
+	}
+
+	v, err := parseBitString(s)
+	if err != nil {
+		return err
+	}
+	*b = v
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (b BitString) Value() (driver.Value, error) {
+	return string(b), nil
+}
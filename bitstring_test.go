@@ -0,0 +1,66 @@
+package pq
+
+import (
+	"bytes"
+	"github.com/lib/pq/oid"
+	"testing"
+)
+
+func TestDecodeBitString(t *testing.T) {
+	for _, typ := range []oid.Oid{oid.T_bit, oid.T_varbit} {
+		got := mustDecode(t, &parameterStatus{}, []byte("10101"), typ, formatText)
+		if got != BitString("10101") {
+			t.Errorf("unexpected bit string %#v", got)
+		}
+	}
+
+	if _, err := parseBitString("1012"); err == nil {
+		t.Error("expected an error for a non-bit character")
+	}
+}
+
+func TestBitStringBytes(t *testing.T) {
+	for _, tt := range []struct {
+		b    BitString
+		want []byte
+	}{
+		{"", []byte{}},
+		{"10101010", []byte{0xaa}},
+		{"1", []byte{0x80}},
+		{"111100001", []byte{0xf0, 0x80}},
+	} {
+		if got := tt.b.Bytes(); !bytes.Equal(got, tt.want) {
+			t.Errorf("%q: expected %x, got %x", tt.b, tt.want, got)
+		}
+	}
+}
+
+func TestBitStringScan(t *testing.T) {
+	var b BitString
+	if err := b.Scan([]byte("0110")); err != nil {
+		t.Fatal(err)
+	}
+	if b != "0110" || b.Len() != 4 {
+		t.Errorf("unexpected bit string %q", b)
+	}
+	if err := b.Scan("01x"); err == nil {
+		t.Error("expected an error for a malformed bit string")
+	}
+	if err := b.Scan(int64(1)); err == nil {
+		t.Error("expected an error for an integer")
+	}
+}
+
+func TestBitStringRoundTrip(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var got BitString
+	err := db.QueryRow("SELECT $1::varbit", BitString("1011")).Scan(&got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "1011" {
+		t.Errorf("expected 1011, got %q", got)
+	}
+}
@@ -0,0 +1,192 @@
+package pq
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+// EncodeBytea encodes v as the text of a bytea, in the hex format, or for a
+// serverVersion before 9.0, the escape format. serverVersion is in the form
+// of the server_version_num setting, such as 90105 for 9.1.5; 0 stands for
+// the latest version.
+func EncodeBytea(serverVersion int, v []byte) []byte {
+	if serverVersion != 0 && serverVersion < 90000 {
+		return encodeByteaEscape(v)
+	}
+	return encodeBytea(v)
+}
+
+// DecodeBytea decodes the text of a bytea, in either the hex or the escape
+// format, as found in query results or COPY data.
+func DecodeBytea(s []byte) ([]byte, error) {
+	return parseBytea(s)
+}
+
+// ByteaWriter returns a sql.Scanner that writes a bytea value to w, such as
+// a hash or a file, rather than into a []byte of the caller's. For example:
+//
+//	h := sha256.New()
+//	err := db.QueryRow("SELECT data FROM blobs WHERE id = $1", id).Scan(pq.ByteaWriter(h))
+//
+// The value is written in a single call to w.Write. NULL cannot be scanned.
+func ByteaWriter(w io.Writer) sql.Scanner {
+	return byteaWriter{w}
+}
+
+type byteaWriter struct {
+	w io.Writer
+}
+
+// Scan implements the Scanner interface.
+func (bw byteaWriter) Scan(src interface{}) error {
+	var err error
+	switch src := src.(type) {
+	case nil:
+		return fmt.Errorf("pq: cannot scan NULL into an io.Writer")
+	case []byte:
+		_, err = bw.w.Write(src)
+	case string:
+		_, err = io.WriteString(bw.w, src)
+	default:
+		return fmt.Errorf("pq: cannot scan %T into an io.Writer", src)
+	}
+	return err
+}
+
+// encodeBytea encodes v for a bytea parameter: in the escape format if the
+// bytea_escape option is set, and otherwise the hex format. Every server
+// understands the escape format, but it can be up to twice as long.
+func (ps *parameterStatus) encodeBytea(v []byte) []byte {
+	if ps.byteaEscape {
+		return encodeByteaEscape(v)
+	}
+	return encodeBytea(v)
+}
+
+// encodeBytea encodes v in the hex format for bytea.
+func encodeBytea(v []byte) []byte {
+	b := make([]byte, 2+hex.EncodedLen(len(v)))
+	b[0] = '\\'
+	b[1] = 'x'
+	hex.Encode(b[2:], v)
+	return b
+}
+
+// encodeByteaEscape encodes v in the escape format for bytea, in which
+// printable ASCII bytes other than the backslash are written as they are.
+func encodeByteaEscape(v []byte) []byte {
+	b := make([]byte, 0, len(v))
+	for _, c := range v {
+		switch {
+		case c == '\\':
+			b = append(b, '\\', '\\')
+		case c < 0x20 || c > 0x7e:
+			b = append(b, '\\', '0'+(c>>6), '0'+(c>>3&7), '0'+(c&7))
+		default:
+			b = append(b, c)
+		}
+	}
+	return b
+}
+
+// parseBytea decodes the text form of a bytea, in either the hex format,
+// or the escape format used by servers before 9.0 or with bytea_output set
+// to escape.
+func parseBytea(s []byte) ([]byte, error) {
+	return parseByteaInto(nil, s)
+}
+
+// parseByteaInto is like parseBytea, but appends the decoded bytes to dst,
+// allocating only if dst lacks the room for them, and returns the extended
+// slice.
+func parseByteaInto(dst, s []byte) ([]byte, error) {
+	if len(s) >= 2 && s[0] == '\\' && s[1] == 'x' {
+		return parseByteaHex(dst, s)
+	}
+
+	// In the escape format, a backslash is written as two of them, and
+	// other bytes may be written as a backslash and three octal digits.
+	b := growBytes(dst, len(s))
+	for len(s) > 0 {
+		// Copy runs of raw bytes, which are most of a typical value, in
+		// one go; a value without escapes is copied whole.
+		if s[0] != '\\' {
+			i := bytes.IndexByte(s, '\\')
+			if i < 0 {
+				i = len(s)
+			}
+			b = append(b, s[:i]...)
+			s = s[i:]
+			continue
+		}
+
+		switch {
+		case len(s) >= 2 && s[1] == '\\':
+			b = append(b, '\\')
+			s = s[2:]
+		case len(s) >= 4 && isOctal(s[1]) && s[1] <= '3' && isOctal(s[2]) && isOctal(s[3]):
+			b = append(b, (s[1]-'0')<<6|(s[2]-'0')<<3|(s[3]-'0'))
+			s = s[4:]
+		default:
+			return nil, fmt.Errorf("pq: invalid bytea sequence %q", s)
+		}
+	}
+	return b, nil
+}
+
+// parseByteaHex decodes a bytea in the hex format, starting with `\x`,
+// appending it to dst. Like Postgres, it allows whitespace between pairs of
+// digits.
+func parseByteaHex(dst, s []byte) ([]byte, error) {
+	b := growBytes(dst, (len(s)-2)/2)
+	for i := 2; i < len(s); i++ {
+		switch s[i] {
+		case ' ', '\t', '\n', '\r', '\f':
+			continue
+		}
+		if i+1 == len(s) {
+			return nil, fmt.Errorf("pq: unable to parse bytea: odd number of hex digits")
+		}
+		hi, ok := fromHexChar(s[i])
+		if !ok {
+			return nil, fmt.Errorf("pq: unable to parse bytea: invalid hex digit %q at offset %d", s[i], i)
+		}
+		lo, ok := fromHexChar(s[i+1])
+		if !ok {
+			return nil, fmt.Errorf("pq: unable to parse bytea: invalid hex digit %q at offset %d", s[i+1], i+1)
+		}
+		b = append(b, hi<<4|lo)
+		i++
+	}
+	return b, nil
+}
+
+// growBytes returns b, or a copy of it, with room for at least n more bytes.
+// The result is never nil.
+func growBytes(b []byte, n int) []byte {
+	if b != nil && cap(b)-len(b) >= n {
+		return b
+	}
+	nb := make([]byte, len(b), 2*cap(b)+n)
+	copy(nb, b)
+	return nb
+}
+
+func fromHexChar(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+func isOctal(c byte) bool {
+	return '0' <= c && c <= '7'
+}
@@ -0,0 +1,250 @@
+package pq
+
+import (
+	"bytes"
+	"github.com/lib/pq/oid"
+	"strings"
+	"testing"
+)
+
+func TestParseBytea(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  []byte
+	}{
+		{`\x`, []byte{}},
+		{`\x0001abff`, []byte{0, 1, 0xab, 0xff}},
+		{`\x00 01
+	AB	ff `, []byte{0, 1, 0xab, 0xff}},
+		{``, []byte{}},
+		{`abc`, []byte("abc")},
+		{`a\\b`, []byte(`a\b`)},
+		{`\\`, []byte(`\`)},
+		{`\000`, []byte{0}},
+		{`\377`, []byte{0xff}},
+		{`ab\001`, []byte{'a', 'b', 1}},
+		{`\001\\`, []byte{1, '\\'}},
+		{`\\\\\001`, []byte{'\\', '\\', 1}},
+		{`abc\\def\001ghi`, []byte("abc\\def\x01ghi")},
+	} {
+		got, err := parseBytea([]byte(tt.input))
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("%q: expected %x, got %x", tt.input, tt.want, got)
+		}
+	}
+}
+
+func TestParseByteaError(t *testing.T) {
+	for _, input := range []string{
+		`\xabc`,
+		`\xzz`,
+		`\x0 1`,
+		`\x00 1`,
+		`\`,
+		`a\`,
+		`\01`,
+		`\400`,
+		`\08a`,
+		`\a`,
+	} {
+		if _, err := parseBytea([]byte(input)); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestEncodeByteaVersion(t *testing.T) {
+	v := []byte("a\\b\x00\x1f ~\x7f\xff")
+	for _, tt := range []struct {
+		version int
+		want    string
+	}{
+		{0, `\x615c62001f207e7fff`},
+		{90000, `\x615c62001f207e7fff`},
+		{80400, `a\\b\000\037 ~\177\377`},
+	} {
+		got := EncodeBytea(tt.version, v)
+		if string(got) != tt.want {
+			t.Errorf("%d: expected %s, got %s", tt.version, tt.want, got)
+		}
+		back, err := DecodeBytea(got)
+		if err != nil {
+			t.Errorf("%d: unexpected error: %v", tt.version, err)
+			continue
+		}
+		if !bytes.Equal(back, v) {
+			t.Errorf("%d: expected %x, got %x", tt.version, v, back)
+		}
+	}
+}
+
+func TestEncodeByteaEscapeOption(t *testing.T) {
+	v := []byte("a\\b\x00\xff")
+	for _, x := range []interface{}{v, string(v)} {
+		got, err := encode(&parameterStatus{byteaEscape: true}, x, oid.T_bytea)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `a\\b\000\377`; string(got) != want {
+			t.Errorf("%T: expected %s, got %s", x, want, got)
+		}
+	}
+	if got := mustEncode(t, v, oid.T_bytea); string(got) != `\x615c6200ff` {
+		t.Errorf("expected the hex format without the option, got %s", got)
+	}
+}
+
+func TestByteaEscapeOption(t *testing.T) {
+	db := openTestConnConninfo(t, "bytea_escape=yes")
+	defer db.Close()
+
+	v := []byte("a\\b\x00\x01\xff")
+	var got []byte
+	var n int
+	err := db.QueryRow("SELECT $1::bytea, octet_length($1::bytea)", v).Scan(&got, &n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, v) || n != len(v) {
+		t.Errorf("expected %x, got %x (%d bytes)", v, got, n)
+	}
+}
+
+func TestParseByteaHexErrorOffset(t *testing.T) {
+	_, err := parseBytea([]byte(`\x00 0g`))
+	if err == nil || !strings.Contains(err.Error(), `invalid hex digit 'g' at offset 6`) {
+		t.Errorf("expected the offending digit and its offset, got %v", err)
+	}
+}
+
+func TestByteaEscapeOutput(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("SET LOCAL bytea_output = 'escape'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []byte
+	err = tx.QueryRow(`SELECT E'a\\\\b\\001\\377'::bytea`).Scan(&got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{'a', '\\', 'b', 1, 0xff}; !bytes.Equal(got, want) {
+		t.Errorf("expected %x, got %x", want, got)
+	}
+}
+
+func TestParseByteaAllocs(t *testing.T) {
+	for _, input := range []string{`\x00010203`, `abc\\def\001ghi`} {
+		s := []byte(input)
+		allocs := testing.AllocsPerRun(100, func() {
+			parseBytea(s)
+		})
+		if allocs != 1 {
+			t.Errorf("%q: expected 1 allocation, got %v", input, allocs)
+		}
+	}
+}
+
+func TestParseByteaInto(t *testing.T) {
+	dst := make([]byte, 0, 16)
+	for _, input := range []string{`\x0102`, `a\\b\001`} {
+		dst = append(dst[:0], "prefix"...)
+		got, err := parseByteaInto(dst, []byte(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := parseBytea([]byte(input))
+		if !bytes.Equal(got, append([]byte("prefix"), want...)) {
+			t.Errorf("%q: unexpected result %q", input, got)
+		}
+		if &got[0] != &dst[:1][0] {
+			t.Errorf("%q: expected dst to be reused", input)
+		}
+	}
+
+	s := []byte(`\x00010203`)
+	allocs := testing.AllocsPerRun(100, func() {
+		parseByteaInto(dst[:0], s)
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations, got %v", allocs)
+	}
+}
+
+func TestByteaRows(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT decode(repeat(to_hex(i), 100 * i), 'hex'), '\x0102'::bytea FROM generate_series(1, 15) i`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	i := 0
+	var prev []byte
+	for rows.Next() {
+		i++
+		var a, b []byte
+		if err := rows.Scan(&a, &b); err != nil {
+			t.Fatal(err)
+		}
+		if len(a) != 50*i || a[0] != byte(i*17) || !bytes.Equal(b, []byte{1, 2}) {
+			t.Errorf("row %d: unexpected values %x, %x", i, a, b)
+		}
+		if prev != nil && (len(prev) != 50*(i-1) || prev[0] != byte((i-1)*17)) {
+			t.Errorf("row %d: the previous row's value changed", i)
+		}
+		prev = a
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestByteaWriter(t *testing.T) {
+	var buf bytes.Buffer
+	s := ByteaWriter(&buf)
+	if err := s.Scan([]byte("\x00\xffab")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Scan("cd"); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "\x00\xffabcd" {
+		t.Errorf("expected %q, got %q", "\x00\xffabcd", got)
+	}
+
+	for _, src := range []interface{}{nil, int64(1)} {
+		if err := s.Scan(src); err == nil {
+			t.Errorf("%#v: expected an error", src)
+		}
+	}
+}
+
+func TestByteaWriterScan(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var buf bytes.Buffer
+	err := db.QueryRow(`SELECT '\x00ff6162'::bytea`).Scan(ByteaWriter(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "\x00\xffab" {
+		t.Errorf("expected %q, got %q", "\x00\xffab", got)
+	}
+}
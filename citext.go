@@ -0,0 +1,38 @@
+package pq
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// CIText represents a value of the citext extension type, text that
+// Postgres compares without regard to case. Since citext is not built in,
+// its values are returned as text; scan them into a CIText to compare them
+// the same way in Go with Equal.
+type CIText string
+
+// Equal reports whether t and s are equal under Unicode case folding.
+func (t CIText) Equal(s string) bool {
+	return strings.EqualFold(string(t), s)
+}
+
+// Scan implements the Scanner interface.
+func (t *CIText) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case CIText:
+		*t = v
+	case []byte:
+		*t = CIText(v)
+	case string:
+		*t = CIText(v)
+	default:
+		return fmt.Errorf("pq: cannot scan %T into CIText", value)
+	}
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (t CIText) Value() (driver.Value, error) {
+	return string(t), nil
+}
@@ -0,0 +1,61 @@
+package pq
+
+import "testing"
+
+func TestCITextEqual(t *testing.T) {
+	for _, tt := range []struct {
+		a    CIText
+		b    string
+		want bool
+	}{
+		{"Hello", "hELLO", true},
+		{"Straße", "STRASSE", false},
+		{"ǅ", "ǆ", true},
+		{"Σίσυφος", "ΣΊΣΥΦΟΣ", true},
+		{"a", "b", false},
+	} {
+		if got := tt.a.Equal(tt.b); got != tt.want {
+			t.Errorf("%q.Equal(%q): expected %v, got %v", tt.a, tt.b, tt.want, got)
+		}
+	}
+}
+
+func TestCITextScan(t *testing.T) {
+	var c CIText
+	if err := c.Scan([]byte("Foo")); err != nil {
+		t.Fatal(err)
+	}
+	if c != "Foo" || !c.Equal("FOO") {
+		t.Errorf("unexpected value %q", c)
+	}
+	if err := c.Scan(nil); err == nil {
+		t.Error("expected an error scanning nil into a CIText")
+	}
+	if v, _ := c.Value(); v != "Foo" {
+		t.Errorf("unexpected value %#v", v)
+	}
+}
+
+func TestCITextRoundTrip(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var exists bool
+	err := db.QueryRow("SELECT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'citext')").Scan(&exists)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists {
+		t.Skip("the citext extension is not installed")
+	}
+
+	var c CIText
+	var same bool
+	err = db.QueryRow("SELECT $1::citext, $1::citext = 'HELLO'", CIText("Hello")).Scan(&c, &same)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c != "Hello" || !same || !c.Equal("HELLO") {
+		t.Errorf("unexpected result %q, %v", c, same)
+	}
+}
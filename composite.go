@@ -0,0 +1,129 @@
+package pq
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// Composite returns a sql.Scanner that scans the fields of a composite
+// value, such as a row of a composite type or an anonymous record, into
+// dest, which must have one element per field. For example:
+//
+//	var street, city string
+//	var zip sql.NullString
+//	err := db.QueryRow("SELECT addr FROM t").Scan(pq.Composite(&street, &city, &zip))
+//
+// Each element of dest may be a sql.Scanner, which is passed the text of
+// its field as a []byte, or a pointer to any type a slice element can be
+// scanned into with Array. Nested composites are passed as their text, so
+// they can be scanned with another Composite. A type can scan itself from a
+// composite value by calling Composite in its Scan method. Composite
+// values, including anonymous records, are otherwise decoded as text.
+func Composite(dest ...interface{}) sql.Scanner {
+	return compositeScanner(dest)
+}
+
+type compositeScanner []interface{}
+
+// Scan implements the Scanner interface.
+func (c compositeScanner) Scan(src interface{}) error {
+	var fields []interface{}
+	switch src := src.(type) {
+	case nil:
+		return fmt.Errorf("pq: cannot scan NULL into a composite")
+	case []byte:
+		var err error
+		if fields, err = parseComposite(src); err != nil {
+			return err
+		}
+	case string:
+		var err error
+		if fields, err = parseComposite([]byte(src)); err != nil {
+			return err
+		}
+	case []interface{}:
+		fields = src
+	default:
+		return fmt.Errorf("pq: cannot scan %T into a composite", src)
+	}
+
+	if len(fields) != len(c) {
+		return fmt.Errorf("pq: cannot scan a composite of %d fields into %d destinations", len(fields), len(c))
+	}
+	for i, f := range fields {
+		if s, ok := c[i].(sql.Scanner); ok {
+			if str, ok := f.(string); ok {
+				f = []byte(str)
+			}
+			if err := s.Scan(f); err != nil {
+				return err
+			}
+			continue
+		}
+
+		dv := reflect.ValueOf(c[i])
+		if dv.Kind() != reflect.Ptr || dv.IsNil() {
+			return fmt.Errorf("pq: cannot scan a composite field into %T; a pointer is required", c[i])
+		}
+		if err := setArrayElem(dv.Elem(), f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseComposite splits the text of a composite value, such as
+// `(1,"a b",,"")`, into the unescaped text of its fields as []byte values,
+// with nil for NULL fields. A field left empty is NULL, while a quoted
+// empty field is an empty string. Within a field, a backslash escapes the
+// next character, and double quotes protect commas, parentheses and spaces;
+// a doubled double quote inside them stands for one.
+func parseComposite(s []byte) ([]interface{}, error) {
+	fail := func() ([]interface{}, error) {
+		return nil, fmt.Errorf("pq: unable to parse composite %q", s)
+	}
+	if len(s) < 2 || s[0] != '(' || s[len(s)-1] != ')' {
+		return fail()
+	}
+
+	var fields []interface{}
+	i := 1
+	for {
+		if s[i] == ',' || s[i] == ')' {
+			fields = append(fields, nil)
+		} else {
+			f := []byte{}
+			quoted := false
+			for ; quoted || (s[i] != ',' && s[i] != ')'); i++ {
+				if i == len(s)-1 {
+					return fail()
+				}
+				switch c := s[i]; {
+				case c == '\\':
+					i++
+					if i == len(s)-1 {
+						return fail()
+					}
+					f = append(f, s[i])
+				case c == '"' && quoted && s[i+1] == '"':
+					f = append(f, '"')
+					i++
+				case c == '"':
+					quoted = !quoted
+				default:
+					f = append(f, c)
+				}
+			}
+			fields = append(fields, f)
+		}
+
+		if s[i] == ')' {
+			if i != len(s)-1 {
+				return fail()
+			}
+			return fields, nil
+		}
+		i++
+	}
+}
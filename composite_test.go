@@ -0,0 +1,142 @@
+package pq
+
+import (
+	"database/sql"
+	"github.com/lib/pq/oid"
+	"reflect"
+	"testing"
+)
+
+func TestParseComposite(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  []interface{}
+	}{
+		{`()`, []interface{}{nil}},
+		{`(1)`, []interface{}{[]byte("1")}},
+		{`(street,"city name",12345)`, []interface{}{[]byte("street"), []byte("city name"), []byte("12345")}},
+		{`(,"",)`, []interface{}{nil, []byte(""), nil}},
+		{`("a,b","(c)","d""e","f\\g",h\,i)`, []interface{}{
+			[]byte("a,b"), []byte("(c)"), []byte(`d"e`), []byte(`f\g`), []byte("h,i"),
+		}},
+		{`(1,"(2,""x y"")")`, []interface{}{[]byte("1"), []byte(`(2,"x y")`)}},
+	} {
+		got, err := parseComposite([]byte(tt.input))
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: expected %#v, got %#v", tt.input, tt.want, got)
+		}
+	}
+
+	for _, input := range []string{``, `(`, `1,2`, `(1,2`, `("a)`, `(a\)`, `(a)b)`, `(1))`} {
+		if _, err := parseComposite([]byte(input)); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestDecodeRecord(t *testing.T) {
+	got := mustDecode(t, &parameterStatus{}, []byte(`(1,,"a b")`), oid.T_record, formatText)
+	if b, ok := got.([]byte); !ok || string(b) != `(1,,"a b")` {
+		t.Errorf("expected the text of the record, got %#v", got)
+	}
+}
+
+type testAddress struct {
+	Street, City string
+	Zip          sql.NullInt64
+}
+
+func (a *testAddress) Scan(src interface{}) error {
+	return Composite(&a.Street, &a.City, &a.Zip).Scan(src)
+}
+
+func TestCompositeScanner(t *testing.T) {
+	var a testAddress
+	if err := a.Scan([]byte(`("1 Main St","Springfield",)`)); err != nil {
+		t.Fatal(err)
+	}
+	if want := (testAddress{Street: "1 Main St", City: "Springfield"}); a != want {
+		t.Errorf("expected %+v, got %+v", want, a)
+	}
+
+	var n int64
+	var inner []byte
+	if err := Composite(&n, &inner).Scan([]interface{}{"42", "(x,y)"}); err != nil {
+		t.Fatal(err)
+	}
+	if n != 42 || string(inner) != "(x,y)" {
+		t.Errorf("expected 42 and (x,y), got %d and %q", n, inner)
+	}
+
+	var x, y string
+	if err := Composite(&x, &y).Scan(inner); err != nil {
+		t.Fatal(err)
+	}
+	if x != "x" || y != "y" {
+		t.Errorf("expected x and y, got %q and %q", x, y)
+	}
+
+	for _, tt := range []struct {
+		src  interface{}
+		dest []interface{}
+	}{
+		{[]byte(`(1,2)`), []interface{}{&n}},
+		{[]byte(`(,2)`), []interface{}{&x, &y}},
+		{[]byte(`(a,2)`), []interface{}{&n, &y}},
+		{[]byte(`(1,2)`), []interface{}{n, &y}},
+		{nil, []interface{}{&x}},
+	} {
+		if err := Composite(tt.dest...).Scan(tt.src); err == nil {
+			t.Errorf("%q: expected an error", tt.src)
+		}
+	}
+}
+
+func TestCompositeScan(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var s string
+	err := db.QueryRow(`SELECT ROW(1, NULL, 'a "b"')`).Scan(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `(1,,"a ""b""")`; s != want {
+		t.Errorf("expected %q, got %q", want, s)
+	}
+
+	var n int64
+	var null sql.NullString
+	var str, inner string
+	err = db.QueryRow(`SELECT ROW(1, NULL, 'a "b"', ROW(2, 'c d'))`).Scan(Composite(&n, &null, &str, &inner))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || null.Valid || str != `a "b"` || inner != `(2,"c d")` {
+		t.Errorf("unexpected fields %d, %#v, %q, %q", n, null, str, inner)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("CREATE TYPE pq_test_address AS (street text, city text, zip int)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var a testAddress
+	err = tx.QueryRow(`SELECT ROW('1 Main St', 'Spring, field', 12345)::pq_test_address`).Scan(&a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := testAddress{"1 Main St", "Spring, field", sql.NullInt64{Int64: 12345, Valid: true}}
+	if a != want {
+		t.Errorf("expected %+v, got %+v", want, a)
+	}
+}
@@ -39,6 +39,61 @@ type conn struct {
 	buf     *bufio.Reader
 	namei   int
 	scratch [512]byte
+
+	parameterStatus parameterStatus
+
+	// Whether to send integer parameters in binary format; set with
+	// the binary_parameters connection option.
+	binaryParameters bool
+
+	// Whether to receive integer and float columns in binary format;
+	// set with the binary_results connection option.
+	binaryResults bool
+}
+
+// parameterStatus holds the session state that affects how values are
+// encoded and decoded: the server's run-time parameters, and the
+// connection options that change how values are represented.
+type parameterStatus struct {
+	// The server's DateStyle, as last reported in a ParameterStatus
+	// message.
+	dateStyle string
+
+	// Whether to trim the padding from char(n) values; set with the
+	// trim_bpchar connection option.
+	trimBpchar bool
+
+	// Whether to send floats with as many digits as their type holds,
+	// rather than the fewest that read back as the same value; set with
+	// the max_float_precision connection option.
+	maxFloatPrecision bool
+
+	// Whether to return timestamptz and timetz values in a fixed zone with
+	// the offset they were sent with, rather than in the local time zone
+	// when it has that offset; set with the fixed_time_zones connection
+	// option.
+	fixedTimeZones bool
+
+	// Whether to return numeric values that are whole numbers, and fit, as
+	// int64s; set with the numeric_as_int64 connection option.
+	numericAsInt64 bool
+
+	// Whether to send parameters that implement fmt.Stringer, but not
+	// driver.Valuer, as the text of their String method; set with the
+	// bind_stringers connection option.
+	bindStringers bool
+
+	// Whether to return timestamptz and timetz values in UTC; set with the
+	// force_utc connection option.
+	forceUTC bool
+
+	// Whether to return int2 and int4 values as int16 and int32, rather
+	// than int64; set with the natural_int_widths connection option.
+	naturalIntWidths bool
+
+	// Whether to send bytea parameters in the escape format rather than the
+	// hex format; set with the bytea_escape connection option.
+	byteaEscape bool
 }
 
 func (c *conn) writeBuf(b byte) *writeBuf {
@@ -79,12 +134,35 @@ func Open(name string) (_ driver.Conn, err error) {
 		}
 	}
 
+	binaryParameters := boolOpt(o, "binary_parameters")
+	binaryResults := boolOpt(o, "binary_results")
+	trimBpchar := boolOpt(o, "trim_bpchar")
+	maxFloatPrecision := boolOpt(o, "max_float_precision")
+	fixedTimeZones := boolOpt(o, "fixed_time_zones")
+	numericAsInt64 := boolOpt(o, "numeric_as_int64")
+	bindStringers := boolOpt(o, "bind_stringers")
+	forceUTC := boolOpt(o, "force_utc")
+	naturalIntWidths := boolOpt(o, "natural_int_widths")
+	byteaEscape := boolOpt(o, "bytea_escape")
+
 	c, err := net.Dial(network(o))
 	if err != nil {
 		return nil, err
 	}
 
-	cn := &conn{c: c}
+	cn := &conn{
+		c:                c,
+		binaryParameters: binaryParameters,
+		binaryResults:    binaryResults,
+	}
+	cn.parameterStatus.trimBpchar = trimBpchar
+	cn.parameterStatus.maxFloatPrecision = maxFloatPrecision
+	cn.parameterStatus.fixedTimeZones = fixedTimeZones
+	cn.parameterStatus.numericAsInt64 = numericAsInt64
+	cn.parameterStatus.bindStringers = bindStringers
+	cn.parameterStatus.forceUTC = forceUTC
+	cn.parameterStatus.naturalIntWidths = naturalIntWidths
+	cn.parameterStatus.byteaEscape = byteaEscape
 	cn.ssl(o)
 	cn.buf = bufio.NewReader(cn.c)
 	cn.startup(o)
@@ -129,6 +207,20 @@ func parseOpts(name string, o Values) {
 	}
 }
 
+// boolOpt returns the value of the yes/no option k, which defaults to no.
+func boolOpt(o Values, k string) bool {
+	switch v := o.Get(k); v {
+	case "yes":
+		return true
+	case "no", "":
+		return false
+	default:
+		errorf(`unsupported %s %q; only "yes" and "no" supported`, k, v)
+	}
+
+	panic("not reached")
+}
+
 func (cn *conn) Begin() (driver.Tx, error) {
 	_, err := cn.Exec("BEGIN", nil)
 	if err != nil {
@@ -217,6 +309,12 @@ func (cn *conn) prepareTo(q, stmtName string) (_ driver.Stmt, err error) {
 				st.rowTyps[i] = r.oid()
 				r.next(8)
 			}
+			if cn.binaryResults {
+				st.rowFmts = make([]format, n)
+				for i, typ := range st.rowTyps {
+					st.rowFmts[i] = resultFormat(typ)
+				}
+			}
 		case 'n':
 			// no data
 		case 'Z':
@@ -323,9 +421,26 @@ func (cn *conn) recv1() (byte, *readBuf) {
 		panic(err)
 	}
 
+	// ParameterStatus messages are left for the caller to skip, but
+	// the values they carry are tracked here.
+	if c == 'S' {
+		r := readBuf(y)
+		cn.processParameterStatus(&r)
+	}
+
 	return c, (*readBuf)(&y)
 }
 
+func (cn *conn) processParameterStatus(r *readBuf) {
+	param := r.string()
+	val := r.string()
+
+	switch param {
+	case "DateStyle":
+		cn.parameterStatus.dateStyle = val
+	}
+}
+
 func (cn *conn) ssl(o Values) {
 	tlsConf := tls.Config{}
 	switch mode := o.Get("sslmode"); mode {
@@ -422,6 +537,7 @@ type stmt struct {
 	query     string
 	cols      []string
 	rowTyps   []oid.Oid
+	rowFmts   []format
 	paramTyps []oid.Oid
 	closed    bool
 }
@@ -456,7 +572,9 @@ func (st *stmt) Close() (err error) {
 
 func (st *stmt) Query(v []driver.Value) (_ driver.Rows, err error) {
 	defer errRecover(&err)
-	st.exec(v)
+	if err := st.exec(v); err != nil {
+		return nil, err
+	}
 	return &rows{st: st}, nil
 }
 
@@ -466,7 +584,9 @@ func (st *stmt) Exec(v []driver.Value) (res driver.Result, err error) {
 	if len(v) == 0 {
 		return st.cn.simpleQuery(st.query)
 	}
-	st.exec(v)
+	if err := st.exec(v); err != nil {
+		return nil, err
+	}
 
 	for {
 		t, r := st.cn.recv1()
@@ -488,22 +608,49 @@ func (st *stmt) Exec(v []driver.Value) (res driver.Result, err error) {
 	panic("not reached")
 }
 
-func (st *stmt) exec(v []driver.Value) {
+// exec binds v to the statement and executes it. Parameters that cannot be
+// encoded are reported before anything is sent to the server.
+func (st *stmt) exec(v []driver.Value) error {
 	w := st.cn.writeBuf('B')
 	w.string("")
 	w.string(st.name)
-	w.int16(0)
+
+	var fmts []format
+	if st.cn.binaryParameters {
+		fmts = make([]format, len(v))
+		for i, x := range v {
+			fmts[i] = paramFormat(x, st.paramTyps[i])
+		}
+	}
+	w.int16(len(fmts))
+	for _, f := range fmts {
+		w.int16(int(f))
+	}
+
 	w.int16(len(v))
 	for i, x := range v {
-		if x == nil {
-			w.int32(-1)
+		var b []byte
+		var err error
+		if fmts != nil && fmts[i] == formatBinary {
+			b, err = encodeBinary(x, st.paramTyps[i])
 		} else {
-			b := encode(x, st.paramTyps[i])
-			w.int32(len(b))
-			w.bytes(b)
+			b, err = encode(&st.cn.parameterStatus, x, st.paramTyps[i])
+		}
+		if err != nil {
+			return err
 		}
+		if b == nil {
+			w.int32(-1)
+			continue
+		}
+		w.int32(len(b))
+		w.bytes(b)
+	}
+
+	w.int16(len(st.rowFmts))
+	for _, f := range st.rowFmts {
+		w.int16(int(f))
 	}
-	w.int16(0)
 	st.cn.send(w)
 
 	w = st.cn.writeBuf('E')
@@ -523,12 +670,12 @@ func (st *stmt) exec(v []driver.Value) {
 			if err != nil {
 				panic(err)
 			}
-			return
+			return nil
 		case 'Z':
 			if err != nil {
 				panic(err)
 			}
-			return
+			return nil
 		case 'N':
 			// ignore
 		default:
@@ -601,7 +748,15 @@ func (rs *rows) Next(dest []driver.Value) (err error) {
 					dest[i] = nil
 					continue
 				}
-				dest[i] = decode(r.next(l), rs.st.rowTyps[i])
+				f := formatText
+				if rs.st.rowFmts != nil {
+					f = rs.st.rowFmts[i]
+				}
+				s := r.next(l)
+				dest[i], err = decode(&rs.st.cn.parameterStatus, s, rs.st.rowTyps[i], f)
+				if err != nil {
+					return decodeError(rs.st.cols[i], rs.st.rowTyps[i], s, err)
+				}
 			}
 			return
 		default:
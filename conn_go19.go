@@ -0,0 +1,36 @@
+//go:build go1.9
+// +build go1.9
+
+package pq
+
+import (
+	"database/sql/driver"
+	"encoding/xml"
+	"fmt"
+	"net"
+	"time"
+)
+
+// CheckNamedValue implements the driver.NamedValueChecker interface. It lets
+// values that encode understands, but database/sql would convert or reject,
+// through to encode: slices, which are sent as arrays, network and MAC
+// addresses, UUIDs as [16]byte, xml.Marshalers, time.Durations, which are
+// sent as intervals where one is expected, unsigned integers, which
+// database/sql rejects above math.MaxInt64, types with a registered
+// encoder, and, with the bind_stringers option, fmt.Stringers. All other
+// values get database/sql's default conversion.
+func (cn *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	switch nv.Value.(type) {
+	case driver.Valuer:
+		return driver.ErrSkip
+	case net.IP, *net.IPNet, net.HardwareAddr, [16]byte, xml.Marshaler, time.Duration, uint64, uint:
+		return nil
+	}
+	if isArrayParam(nv.Value) || registeredEncoder(nv.Value) != nil {
+		return nil
+	}
+	if _, ok := nv.Value.(fmt.Stringer); ok && cn.parameterStatus.bindStringers {
+		return nil
+	}
+	return driver.ErrSkip
+}
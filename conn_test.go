@@ -15,6 +15,12 @@ type Fatalistic interface {
 }
 
 func openTestConn(t Fatalistic) *sql.DB {
+	return openTestConnConninfo(t, "")
+}
+
+// openTestConnConninfo is like openTestConn, but passes the connection
+// options in conninfo to the driver.
+func openTestConnConninfo(t Fatalistic, conninfo string) *sql.DB {
 	datname := os.Getenv("PGDATABASE")
 	sslmode := os.Getenv("PGSSLMODE")
 
@@ -26,7 +32,7 @@ func openTestConn(t Fatalistic) *sql.DB {
 		os.Setenv("PGSSLMODE", "disable")
 	}
 
-	conn, err := sql.Open("postgres", "")
+	conn, err := sql.Open("postgres", conninfo)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -261,6 +267,57 @@ func TestNoData(t *testing.T) {
 	}
 }
 
+func TestBinaryParameters(t *testing.T) {
+	db := openTestConnConninfo(t, "binary_parameters=yes")
+	defer db.Close()
+
+	var ok bool
+	err := db.QueryRow("SELECT $1::int2 = -2 AND $2::int4 = 258 AND $3::int8 = 1 << 40 AND $4::text = '7'",
+		-2, 258, int64(1)<<40, 7).Scan(&ok)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Error("expected the integer parameters to round-trip")
+	}
+
+	_, err = db.Exec("SELECT $1::int2", 1<<20)
+	if err == nil {
+		t.Error("expected an error for an out-of-range int2")
+	}
+}
+
+func TestBinaryResults(t *testing.T) {
+	db := openTestConnConninfo(t, "binary_results=yes")
+	defer db.Close()
+
+	var (
+		i2, i4, i8 int64
+		f4, f8     float64
+		s          string
+	)
+	err := db.QueryRow("SELECT -2::int2, 258::int4, 1::int8 << 40, 1.5::float4, -0.25::float8, 'x'::text").
+		Scan(&i2, &i4, &i8, &f4, &f8, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i2 != -2 || i4 != 258 || i8 != 1<<40 || f4 != 1.5 || f8 != -0.25 || s != "x" {
+		t.Errorf("unexpected values: %v %v %v %v %v %q", i2, i4, i8, f4, f8, s)
+	}
+}
+
+func TestBinaryParametersOption(t *testing.T) {
+	db, err := sql.Open("postgres", "binary_parameters=maybe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err = db.Ping(); err == nil {
+		t.Fatal("expected an error for an invalid binary_parameters value")
+	}
+}
+
 func TestPGError(t *testing.T) {
 	// Don't use the normal connection setup, this is intended to
 	// blow up in the startup packet from a non-existent user.
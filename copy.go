@@ -0,0 +1,77 @@
+package pq
+
+import (
+	"database/sql/driver"
+	"github.com/lib/pq/oid"
+)
+
+// CopyData encodes rows in the text format of COPY ... FROM STDIN: one line
+// per row, with its fields separated by tabs. Fields are encoded as query
+// parameters would be, including through driver.Valuer; []byte fields are
+// encoded as bytea, and nil as NULL (\N). Backslashes, tabs, newlines,
+// carriage returns, vertical tabs and form feeds in the fields are escaped.
+func CopyData(rows [][]interface{}) ([]byte, error) {
+	var b []byte
+	for _, row := range rows {
+		for i, x := range row {
+			if i > 0 {
+				b = append(b, '\t')
+			}
+			var err error
+			if b, err = appendCopyField(b, x); err != nil {
+				return nil, err
+			}
+		}
+		b = append(b, '\n')
+	}
+	return b, nil
+}
+
+// appendCopyField appends the COPY text of x to b.
+func appendCopyField(b []byte, x interface{}) ([]byte, error) {
+	if v, ok := x.(driver.Valuer); ok {
+		var err error
+		if x, err = callValuer(v); err != nil {
+			return nil, err
+		}
+	}
+
+	var e []byte
+	switch v := x.(type) {
+	case nil:
+		return append(b, `\N`...), nil
+	case []byte:
+		if v == nil {
+			return append(b, `\N`...), nil
+		}
+		e = encodeBytea(v)
+	default:
+		var err error
+		if e, err = encode(&parameterStatus{}, x, oid.T_unknown); err != nil {
+			return nil, err
+		}
+		if e == nil {
+			return append(b, `\N`...), nil
+		}
+	}
+
+	for _, c := range e {
+		switch c {
+		case '\\':
+			b = append(b, `\\`...)
+		case '\t':
+			b = append(b, `\t`...)
+		case '\n':
+			b = append(b, `\n`...)
+		case '\r':
+			b = append(b, `\r`...)
+		case '\v':
+			b = append(b, `\v`...)
+		case '\f':
+			b = append(b, `\f`...)
+		default:
+			b = append(b, c)
+		}
+	}
+	return b, nil
+}
@@ -0,0 +1,39 @@
+package pq
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestCopyData(t *testing.T) {
+	got, err := CopyData([][]interface{}{
+		{int64(1), "plain", true},
+		{int64(-2), "tab\there\nnewline\rreturn\\backslash", false},
+		{nil, []byte("\x00\xff"), []byte(nil)},
+		{2.5, time.Date(2012, 11, 6, 10, 23, 42, 0, time.UTC), sql.NullString{}},
+		{sql.NullString{String: `\N`, Valid: true}, "", (*int64)(nil)},
+		{int64(3), "vertical\vtab\fform feed", true},
+		{(*sql.NullString)(nil), (*NullTime)(nil), (*NullBytea)(nil)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "1\tplain\ttrue\n" +
+		"-2\ttab\\there\\nnewline\\rreturn\\\\backslash\tfalse\n" +
+		"\\N\t\\\\x00ff\t\\N\n" +
+		"2.5\t2012-11-06 10:23:42Z\t\\N\n" +
+		"\\\\N\t\t\\N\n" +
+		"3\tvertical\\vtab\\fform feed\ttrue\n" +
+		"\\N\t\\N\t\\N\n"
+	if string(got) != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	if got, err := CopyData(nil); err != nil || len(got) != 0 {
+		t.Errorf("expected no data, got %q, %v", got, err)
+	}
+	if _, err := CopyData([][]interface{}{{struct{}{}}}); err == nil {
+		t.Error("expected an error for a value that cannot be encoded")
+	}
+}
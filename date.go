@@ -0,0 +1,80 @@
+package pq
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// Date represents a Postgres date: a day in the proleptic Gregorian
+// calendar, without a time of day or time zone. Years BC are the years 0
+// and before, so that 1 BC is the year 0, as with time.Time.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// DateOf returns the date of t in t's location.
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{y, m, d}
+}
+
+// Time returns midnight at the start of the date in loc.
+func (d Date) Time(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// Before reports whether d is before e.
+func (d Date) Before(e Date) bool {
+	if d.Year != e.Year {
+		return d.Year < e.Year
+	}
+	if d.Month != e.Month {
+		return d.Month < e.Month
+	}
+	return d.Day < e.Day
+}
+
+// String returns the date in the format Postgres uses, such as
+// "2012-11-06" or "0044-03-15 BC".
+func (d Date) String() string {
+	if d.Year <= 0 {
+		return fmt.Sprintf("%04d-%02d-%02d BC", 1-d.Year, int(d.Month), d.Day)
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, int(d.Month), d.Day)
+}
+
+// Scan implements the Scanner interface. Besides dates, it accepts
+// time.Time values and the text of timestamps, whose time of day is
+// dropped.
+func (d *Date) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case Date:
+		*d = v
+		return nil
+	case time.Time:
+		*d = DateOf(v)
+		return nil
+	case []byte:
+		return d.scanText(string(v))
+	case string:
+		return d.scanText(v)
+	}
+	return fmt.Errorf("pq: cannot scan %T into Date", value)
+}
+
+func (d *Date) scanText(s string) error {
+	t, err := parseTs(nil, s)
+	if err != nil {
+		return err
+	}
+	*d = DateOf(t)
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (d Date) Value() (driver.Value, error) {
+	return d.String(), nil
+}
@@ -0,0 +1,92 @@
+package pq
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateString(t *testing.T) {
+	for _, tt := range []struct {
+		d    Date
+		want string
+	}{
+		{Date{2012, time.November, 6}, "2012-11-06"},
+		{Date{1969, time.December, 31}, "1969-12-31"},
+		{Date{1, time.January, 1}, "0001-01-01"},
+		{Date{0, time.December, 31}, "0001-12-31 BC"},
+		{Date{-43, time.March, 15}, "0044-03-15 BC"},
+		{Date{12345, time.June, 7}, "12345-06-07"},
+	} {
+		if got := tt.d.String(); got != tt.want {
+			t.Errorf("%#v: expected %q, got %q", tt.d, tt.want, got)
+		}
+
+		var back Date
+		if err := back.Scan(tt.want); err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.want, err)
+		} else if back != tt.d {
+			t.Errorf("%q: expected %#v, got %#v", tt.want, tt.d, back)
+		}
+	}
+}
+
+func TestDateScan(t *testing.T) {
+	want := Date{2012, time.November, 6}
+	for _, src := range []interface{}{
+		want,
+		time.Date(2012, 11, 6, 0, 0, 0, 0, time.UTC),
+		time.Date(2012, 11, 6, 23, 59, 0, 0, time.FixedZone("", -8*60*60)),
+		[]byte("2012-11-06"),
+		"2012-11-06 10:23:42+01",
+	} {
+		var d Date
+		if err := d.Scan(src); err != nil {
+			t.Errorf("%#v: unexpected error: %v", src, err)
+			continue
+		}
+		if d != want {
+			t.Errorf("%#v: expected %v, got %v", src, want, d)
+		}
+	}
+
+	var d Date
+	for _, src := range []interface{}{nil, int64(1), "2012-13-01", "Nov 6"} {
+		if err := d.Scan(src); err == nil {
+			t.Errorf("%#v: expected an error", src)
+		}
+	}
+}
+
+func TestDateCompare(t *testing.T) {
+	a, b := Date{2012, time.November, 6}, Date{2012, time.November, 7}
+	if !a.Before(b) || b.Before(a) || a.Before(a) {
+		t.Error("unexpected ordering")
+	}
+	if got := a.Time(time.UTC); !got.Equal(time.Date(2012, 11, 6, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected time %v", got)
+	}
+}
+
+func TestDateRoundTrip(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	for _, d := range []Date{
+		{2012, time.November, 6},
+		{1900, time.February, 28},
+		{-43, time.March, 15},
+	} {
+		var back Date
+		var text string
+		err := db.QueryRow("SELECT $1::date, $1::date::text", d).Scan(&back, &text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if back != d {
+			t.Errorf("expected %v, got %v", d, back)
+		}
+		if text != d.String() {
+			t.Errorf("expected %q, got %q", d.String(), text)
+		}
+	}
+}
@@ -1,116 +1,610 @@
 package pq
 
 import (
+	"bytes"
 	"database/sql/driver"
-	"encoding/hex"
+	"encoding/binary"
+	"encoding/xml"
 	"fmt"
 	"github.com/lib/pq/oid"
+	"math"
+	"net"
+	"reflect"
 	"strconv"
+	"strings"
 	"time"
 )
 
-func encode(x interface{}, pgtypOid oid.Oid) []byte {
+// encode returns the text form of x as a value of type pgtypOid, or nil if x
+// is a nil pointer or interface, or a Valuer whose value is nil, which are
+// sent as NULL.
+func encode(ps *parameterStatus, x interface{}, pgtypOid oid.Oid) ([]byte, error) {
 	switch v := x.(type) {
+	case nil:
+		return nil, nil
 	case int64:
-		return []byte(fmt.Sprintf("%d", v))
-	case float32, float64:
-		return []byte(fmt.Sprintf("%f", v))
+		return encodeInt(v), nil
+	case int:
+		return encodeInt(int64(v)), nil
+	case int32:
+		return encodeInt(int64(v)), nil
+	case int16:
+		return encodeInt(int64(v)), nil
+	case int8:
+		return encodeInt(int64(v)), nil
+	case uint64:
+		return encodeUint(v, pgtypOid)
+	case uint:
+		return encodeUint(uint64(v), pgtypOid)
+	case uint32:
+		return encodeUint(uint64(v), pgtypOid)
+	case uint16:
+		return encodeUint(uint64(v), pgtypOid)
+	case uint8:
+		return encodeUint(uint64(v), pgtypOid)
+	case float64:
+		return encodeFloat(ps, v, 64), nil
+	case float32:
+		return encodeFloat(ps, float64(v), 32), nil
 	case []byte:
 		if pgtypOid == oid.T_bytea {
-			return []byte(fmt.Sprintf("\\x%x", v))
+			return ps.encodeBytea(v), nil
 		}
 
-		return v
+		return v, nil
 	case string:
 		if pgtypOid == oid.T_bytea {
-			return []byte(fmt.Sprintf("\\x%x", v))
+			return ps.encodeBytea([]byte(v)), nil
 		}
 
-		return []byte(v)
+		return []byte(v), nil
 	case bool:
-		return []byte(fmt.Sprintf("%t", v))
+		return strconv.AppendBool(make([]byte, 0, 5), v), nil
 	case time.Time:
-		return []byte(v.Format(time.RFC3339Nano))
+		switch {
+		case v.Equal(InfinityTime):
+			return []byte("infinity"), nil
+		case v.Equal(NegInfinityTime):
+			return []byte("-infinity"), nil
+		}
+		return formatTs(v, true), nil
+	case time.Duration:
+		if pgtypOid == oid.T_interval {
+			return encodeDuration(v), nil
+		}
+		// Otherwise, send the nanoseconds, as database/sql would
+		return strconv.AppendInt(make([]byte, 0, 20), int64(v), 10), nil
+	case net.IP:
+		return encodeIP(v)
+	case *net.IPNet:
+		return encodeIPNet(v)
+	case net.HardwareAddr:
+		return []byte(v.String()), nil
+	case [16]byte:
+		return encodeUUID(v), nil
+	case driver.Valuer:
+		dv, err := callValuer(v)
+		if err != nil {
+			return nil, err
+		}
+		return encode(ps, dv, pgtypOid)
+	case xml.Marshaler:
+		b, err := xml.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("pq: encode: %s", err)
+		}
+		return b, nil
 	default:
-		errorf("encode: unknown type for %T", v)
+		if enc := registeredEncoder(v); enc != nil {
+			return enc(v)
+		}
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return nil, nil
+		}
+		if s, ok := v.(fmt.Stringer); ok && ps.bindStringers {
+			return []byte(s.String()), nil
+		}
+		if rv.Kind() == reflect.Ptr {
+			return encode(ps, rv.Elem().Interface(), pgtypOid)
+		}
+		if isArrayParam(v) {
+			return encodeArray(ps, rv, pgtypOid)
+		}
 	}
+	return nil, fmt.Errorf("pq: encode: unknown type for %T", x)
+}
+
+// format is a Postgres wire format code.
+type format int16
 
-	panic("not reached")
+const (
+	formatText   format = 0
+	formatBinary format = 1
+)
+
+// paramFormat returns the format in which x is sent when it is bound to a
+// parameter of type pgtypOid. Only integers are sent in binary.
+func paramFormat(x interface{}, pgtypOid oid.Oid) format {
+	if _, ok := x.(int64); !ok {
+		return formatText
+	}
+	switch pgtypOid {
+	case oid.T_int2, oid.T_int4, oid.T_int8:
+		return formatBinary
+	}
+	return formatText
 }
 
-func decode(s []byte, typ oid.Oid) interface{} {
+// encodeBinary encodes x in the binary format of pgtypOid, for which
+// paramFormat must have returned formatBinary.
+func encodeBinary(x interface{}, pgtypOid oid.Oid) ([]byte, error) {
+	v := x.(int64)
+	switch pgtypOid {
+	case oid.T_int2:
+		if v < math.MinInt16 || v > math.MaxInt16 {
+			return nil, fmt.Errorf("pq: encode: %d is out of range for int2", v)
+		}
+		b := make([]byte, 2)
+		binary.BigEndian.PutUint16(b, uint16(v))
+		return b, nil
+	case oid.T_int4:
+		if v < math.MinInt32 || v > math.MaxInt32 {
+			return nil, fmt.Errorf("pq: encode: %d is out of range for int4", v)
+		}
+		b := make([]byte, 4)
+		binary.BigEndian.PutUint32(b, uint32(v))
+		return b, nil
+	case oid.T_int8:
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, uint64(v))
+		return b, nil
+	}
+	return nil, fmt.Errorf("pq: encode: no binary format for %T as type %d", x, pgtypOid)
+}
+
+// resultFormat returns the format in which columns of type typ are
+// requested when binary results are enabled. Only integers and floats are
+// received in binary.
+func resultFormat(typ oid.Oid) format {
+	switch typ {
+	case oid.T_int2, oid.T_int4, oid.T_int8, oid.T_float4, oid.T_float8:
+		return formatBinary
+	}
+	return formatText
+}
+
+// decodeBinary decodes s from the binary format of typ, for which
+// resultFormat must have returned formatBinary.
+func decodeBinary(s []byte, typ oid.Oid) (interface{}, error) {
+	var size int
+	switch typ {
+	case oid.T_int2:
+		size = 2
+	case oid.T_int4, oid.T_float4:
+		size = 4
+	case oid.T_int8, oid.T_float8:
+		size = 8
+	default:
+		return nil, fmt.Errorf("pq: decode: no binary format for type %d", typ)
+	}
+	if len(s) != size {
+		return nil, fmt.Errorf("pq: decode: expected %d bytes for type %d, got %d", size, typ, len(s))
+	}
+
+	switch typ {
+	case oid.T_int2:
+		return int64(int16(binary.BigEndian.Uint16(s))), nil
+	case oid.T_int4:
+		return int64(int32(binary.BigEndian.Uint32(s))), nil
+	case oid.T_int8:
+		return int64(binary.BigEndian.Uint64(s)), nil
+	case oid.T_float4:
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(s))), nil
+	}
+	return math.Float64frombits(binary.BigEndian.Uint64(s)), nil
+}
+
+// callValuer returns v's value, treating a nil pointer that implements
+// Valuer with a value receiver as NULL instead of letting the call panic.
+func callValuer(v driver.Valuer) (driver.Value, error) {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() &&
+		rv.Type().Elem().Implements(reflect.TypeOf((*driver.Valuer)(nil)).Elem()) {
+		return nil, nil
+	}
+	return v.Value()
+}
+
+func encodeInt(v int64) []byte {
+	return strconv.AppendInt(make([]byte, 0, 20), v, 10)
+}
+
+// encodeUint formats v in decimal. Postgres has no unsigned integer types,
+// so values too large for an int2, int4 or int8 pgtypOid are an error
+// rather than one the server reports; a numeric holds any of them.
+func encodeUint(v uint64, pgtypOid oid.Oid) ([]byte, error) {
+	var max uint64
+	var name string
+	switch pgtypOid {
+	case oid.T_int2:
+		max, name = math.MaxInt16, "int2"
+	case oid.T_int4:
+		max, name = math.MaxInt32, "int4"
+	case oid.T_int8:
+		max, name = math.MaxInt64, "int8"
+	}
+	if name != "" && v > max {
+		return nil, fmt.Errorf("pq: encode: %d is out of range for %s", v, name)
+	}
+	return strconv.AppendUint(make([]byte, 0, 20), v, 10), nil
+}
+
+// encodeFloat formats f in the shortest form that reads back as the same
+// value at the given bit size, or with all 9 or 17 significant digits if
+// ps.maxFloatPrecision is set, using the spellings Postgres accepts for the
+// special values.
+func encodeFloat(ps *parameterStatus, f float64, bitSize int) []byte {
+	switch {
+	case math.IsNaN(f):
+		return []byte("NaN")
+	case math.IsInf(f, 1):
+		return []byte("Infinity")
+	case math.IsInf(f, -1):
+		return []byte("-Infinity")
+	}
+	prec := -1
+	if ps.maxFloatPrecision {
+		prec = 17
+		if bitSize == 32 {
+			prec = 9
+		}
+	}
+	return strconv.AppendFloat(make([]byte, 0, 24), f, 'g', prec, bitSize)
+}
+
+// decodeError adds the column, its type and its value, truncated if it is
+// long, to err, an error decoding the value.
+func decodeError(col string, typ oid.Oid, s []byte, err error) error {
+	name, ok := oid.TypeName[typ]
+	if !ok {
+		name = "type " + strconv.FormatUint(uint64(typ), 10)
+	}
+	const max = 64
+	value := fmt.Sprintf("%q", s)
+	if len(s) > max {
+		value = fmt.Sprintf("%q...", s[:max])
+	}
+	return fmt.Errorf("pq: cannot decode column %q (%s) value %s: %s",
+		col, name, value, strings.TrimPrefix(err.Error(), "pq: "))
+}
+
+// decode converts s, a value of type typ in the format f, to the Go value
+// returned for it in query results.
+func decode(ps *parameterStatus, s []byte, typ oid.Oid, f format) (v interface{}, err error) {
+	if f == formatBinary {
+		v, err = decodeBinary(s, typ)
+		return ps.narrowInt(v, typ), err
+	}
+
 	switch typ {
 	case oid.T_bytea:
-		s = s[2:] // trim off "\\x"
-		d := make([]byte, hex.DecodedLen(len(s)))
-		_, err := hex.Decode(d, s)
-		if err != nil {
-			errorf("%s", err)
+		v, err = parseBytea(s)
+	case oid.T_numeric:
+		// Return the exact digits, rather than risk losing precision in
+		// a float64; they can be scanned into a string, or by a Scanner
+		// such as one backed by big.Rat.
+		v = string(s)
+		if ps.numericAsInt64 && isIntegral(s) {
+			if i, err := strconv.ParseInt(string(s), 10, 64); err == nil {
+				v = i
+			}
+		}
+	case oid.T_timestamptz, oid.T_timestamp, oid.T_date:
+		if err = ps.checkDateStyle(); err == nil {
+			var t time.Time
+			t, err = parseTs(ps.location(), string(s))
+			v = ps.inUTC(t)
 		}
-		return d
-	case oid.T_timestamptz:
-		return mustParse("2006-01-02 15:04:05-07", typ, s)
-	case oid.T_timestamp:
-		return mustParse("2006-01-02 15:04:05", typ, s)
 	case oid.T_time:
-		return mustParse("15:04:05", typ, s)
+		v, err = parseTime(string(s))
 	case oid.T_timetz:
-		return mustParse("15:04:05-07", typ, s)
-	case oid.T_date:
-		return mustParse("2006-01-02", typ, s)
+		var t time.Time
+		t, err = parseTimetz(ps.location(), string(s))
+		v = ps.inUTC(t)
+	case oid.T_void:
+		// The result of a function returning void, which has no value;
+		// it is not NULL, though, so it is the empty string.
+		v = ""
+	case oid.T_name, oid.T_unknown:
+		// Untyped literals, as in SELECT 'hello', have the type unknown.
+		v = string(s)
+	case oid.T_regproc, oid.T_regprocedure, oid.T_regoper, oid.T_regoperator,
+		oid.T_regclass, oid.T_regtype, oid.T_regconfig, oid.T_regdictionary,
+		oid.T_regnamespace, oid.T_regrole:
+		// The object identifier types are written as the objects' names.
+		v = string(s)
+	case oid.T_char:
+		v, err = parseChar(s)
+	case oid.T_bpchar:
+		if ps.trimBpchar {
+			s = bytes.TrimRight(s, " ")
+		}
+		v = append([]byte(nil), s...)
+	case oid.T_bit, oid.T_varbit:
+		v, err = parseBitString(string(s))
+	case oid.T_money:
+		v, err = parseMoney(string(s))
 	case oid.T_bool:
-		return s[0] == 't'
+		v, err = parseBool(s)
 	case oid.T_int8, oid.T_int2, oid.T_int4:
-		i, err := strconv.ParseInt(string(s), 10, 64)
-		if err != nil {
-			errorf("%s", err)
-		}
-		return i
+		v, err = strconv.ParseInt(string(s), 10, 64)
+		v = ps.narrowInt(v, typ)
+	case oid.T_oid, oid.T_xid, oid.T_cid:
+		// Unsigned 32-bit identifiers. The reg* types are left as text,
+		// as they are written as names, such as "pg_class".
+		var i uint64
+		i, err = strconv.ParseUint(string(s), 10, 32)
+		v = int64(i)
 	case oid.T_float4, oid.T_float8:
 		bits := 64
 		if typ == oid.T_float4 {
 			bits = 32
 		}
-		f, err := strconv.ParseFloat(string(s), bits)
-		if err != nil {
-			errorf("%s", err)
+		switch string(s) {
+		case "Infinity":
+			v = math.Inf(1)
+		case "-Infinity":
+			v = math.Inf(-1)
+		case "NaN":
+			v = math.NaN()
+		default:
+			v, err = strconv.ParseFloat(string(s), bits)
+		}
+	default:
+		if dec := registeredDecoder(typ); dec != nil {
+			v, err = dec(s)
+		} else {
+			// Copy, as s is only valid until the next row is read, and
+			// the value may be kept by the application, e.g. when it is
+			// scanned into an interface{}.
+			v = append([]byte(nil), s...)
 		}
-		return f
 	}
 
-	return s
+	if err != nil {
+		if _, ok := err.(*strconv.NumError); ok {
+			err = fmt.Errorf("pq: %s", err)
+		}
+		return nil, err
+	}
+	return v, nil
 }
 
-func mustParse(f string, typ oid.Oid, s []byte) time.Time {
-	str := string(s)
+// parseMoney converts the text of a money value, such as "-$1,234.56" or
+// "($1.00)", into a plain decimal string such as "-1234.56".
+//
+// How money is rendered depends on the session's lc_monetary, which the
+// server does not report, so the separators are inferred: if both '.' and
+// ',' occur, the last one is the decimal point; if one of them occurs more
+// than once, it separates thousands; if it occurs once, it is the decimal
+// point, unless it is followed by exactly three digits and preceded by
+// more than a zero. That case, such as "¥1,234" or "BD 1.234", could be
+// either, so the text is returned as it is; cast such values to numeric to
+// get a plain decimal.
+func parseMoney(str string) (string, error) {
+	neg := strings.IndexByte(str, '-') >= 0 ||
+		(strings.HasPrefix(str, "(") && strings.HasSuffix(str, ")"))
 
-	// Special case until time.Parse bug is fixed:
-	// http://code.google.com/p/go/issues/detail?id=3487
-	if str[len(str)-2] == '.' {
-		str += "0"
+	var digits []byte
+	dec := -1 // index into digits of the decimal point, if any
+	var seps []byte
+	for i := 0; i < len(str); i++ {
+		switch c := str[i]; {
+		case c >= '0' && c <= '9':
+			digits = append(digits, c)
+		case c == '.' || c == ',':
+			if len(digits) > 0 {
+				seps = append(seps, c)
+				dec = len(digits)
+			}
+		}
+	}
+	if len(digits) == 0 {
+		return "", fmt.Errorf("pq: unable to parse money value %q", str)
 	}
 
-	// check for a 30-minute-offset timezone
-	if (typ == oid.T_timestamptz || typ == oid.T_timetz) &&
-		str[len(str)-3] == ':' {
-		f += ":00"
+	if len(seps) > 0 {
+		last := seps[len(seps)-1]
+		mixed := strings.IndexByte(string(seps), '.') >= 0 &&
+			strings.IndexByte(string(seps), ',') >= 0
+		if !mixed && len(seps) > 1 {
+			dec = -1
+		} else if !mixed && len(digits)-dec == 3 && !(dec == 1 && digits[0] == '0') {
+			return str, nil
+		} else if mixed && strings.IndexByte(string(seps), last) != len(seps)-1 {
+			return "", fmt.Errorf("pq: unable to parse money value %q", str)
+		}
 	}
-	t, err := time.Parse(f, str)
-	if err != nil {
-		errorf("decode: %s", err)
+
+	m := string(digits)
+	if dec >= 0 {
+		m = m[:dec] + "." + m[dec:]
+	}
+	if neg {
+		m = "-" + m
+	}
+	return m, nil
+}
+
+// Postgres dates and timestamps can be 'infinity' or '-infinity'. These are
+// decoded as, respectively, InfinityTime and NegInfinityTime, which lie
+// outside of the range of values Postgres can otherwise represent, and
+// encoded from them in turn.
+var (
+	InfinityTime    = time.Date(5874898, time.January, 1, 0, 0, 0, 0, time.UTC)
+	NegInfinityTime = time.Date(-4713, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// formatTs formats t as a timestamp, with its zone offset if withZone is
+// set. Years before 1 AD are written the way Postgres writes them, as a
+// positive year with a BC suffix, since the year 0 is 1 BC. Postgres only
+// stores microseconds, so t is rounded to the microsecond first, and reads
+// back as the value sent.
+func formatTs(t time.Time, withZone bool) []byte {
+	t = t.Round(time.Microsecond)
+	year, bc := t.Year(), false
+	if year <= 0 {
+		year, bc = 1-year, true
+	}
+
+	b := make([]byte, 0, len("2006-01-02 15:04:05.999999-07:00:00 BC"))
+	y := strconv.Itoa(year)
+	for i := len(y); i < 4; i++ {
+		b = append(b, '0')
+	}
+	b = append(b, y...)
+	if withZone {
+		b = t.AppendFormat(b, "-01-02 15:04:05.999999Z07:00:00")
+	} else {
+		b = t.AppendFormat(b, "-01-02 15:04:05.999999")
+	}
+	if bc {
+		b = append(b, " BC"...)
+	}
+	return b
+}
+
+// isIntegral reports whether s, the text of a numeric, is a whole number
+// without a fractional part, such as "-42" but not "42.0".
+func isIntegral(s []byte) bool {
+	if len(s) > 0 && s[0] == '-' {
+		s = s[1:]
+	}
+	if len(s) == 0 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// parseChar parses the text of a "char", the single-byte type of catalog
+// columns such as pg_attribute.attstorage, into a string of that one byte.
+// Postgres writes the zero byte as an empty string, and bytes outside
+// ASCII as a backslash and three octal digits.
+func parseChar(s []byte) (string, error) {
+	switch {
+	case len(s) == 0:
+		return "\x00", nil
+	case len(s) == 1:
+		return string(s), nil
+	case len(s) == 4 && s[0] == '\\':
+		if n, err := strconv.ParseUint(string(s[1:]), 8, 8); err == nil {
+			return string([]byte{byte(n)}), nil
+		}
+	}
+	return "", fmt.Errorf("pq: unable to parse \"char\" %q", s)
+}
+
+// parseBool parses a bool in any of the spellings Postgres accepts: true,
+// yes, on and 1, and false, no, off and 0, in any case, with surrounding
+// whitespace, and abbreviated to any unambiguous prefix.
+func parseBool(s []byte) (bool, error) {
+	if len(s) == 1 {
+		// The server's own output
+		switch s[0] {
+		case 't':
+			return true, nil
+		case 'f':
+			return false, nil
+		}
+	}
+
+	str := strings.ToLower(strings.TrimSpace(string(s)))
+	if str != "" {
+		switch {
+		case strings.HasPrefix("true", str), strings.HasPrefix("yes", str), str == "on", str == "1":
+			return true, nil
+		case strings.HasPrefix("false", str), strings.HasPrefix("no", str), str == "of", str == "off", str == "0":
+			return false, nil
+		}
+	}
+	return false, fmt.Errorf("pq: invalid input syntax for type bool: %q", s)
+}
+
+// location returns the location that times with a zone offset are
+// returned in when it has the same offset, or nil if they are always to be
+// returned in a fixed zone with the offset they were sent with.
+func (ps *parameterStatus) location() *time.Location {
+	if ps.fixedTimeZones {
+		return nil
+	}
+	return time.Local
+}
+
+// narrowInt converts v, an int2 or int4 decoded as an int64, to an int16
+// or int32 if the natural_int_widths option is set. Other values are
+// returned as they are.
+func (ps *parameterStatus) narrowInt(v interface{}, typ oid.Oid) interface{} {
+	i, ok := v.(int64)
+	if !ok || !ps.naturalIntWidths {
+		return v
+	}
+	switch typ {
+	case oid.T_int2:
+		return int16(i)
+	case oid.T_int4:
+		return int32(i)
+	}
+	return v
+}
+
+// inUTC converts t to UTC if the force_utc option is set. Times without a
+// time zone are always in UTC already.
+func (ps *parameterStatus) inUTC(t time.Time) time.Time {
+	if ps.forceUTC {
+		return t.UTC()
 	}
 	return t
 }
 
+// checkDateStyle ensures that dates and timestamps are sent in the ISO
+// format, the only one decode understands.
+func (ps *parameterStatus) checkDateStyle() error {
+	if ps.dateStyle != "" && !strings.HasPrefix(ps.dateStyle, "ISO") {
+		return fmt.Errorf("pq: unsupported DateStyle %q; only ISO is supported", ps.dateStyle)
+	}
+	return nil
+}
+
+// NullTime represents a time.Time that may be NULL.
 type NullTime struct {
 	Time  time.Time
 	Valid bool // Valid is true if Time is not NULL
 }
 
-// Scan implements the Scanner interface.
-func (nt *NullTime) Scan(value interface{}) error {
-	nt.Time, nt.Valid = value.(time.Time)
-	return nil
+// Scan implements the Scanner interface. Besides time.Time values, it
+// accepts the text of a date, timestamp or timestamptz.
+func (nt *NullTime) Scan(value interface{}) (err error) {
+	switch v := value.(type) {
+	case nil:
+		nt.Time, nt.Valid = time.Time{}, false
+	case time.Time:
+		nt.Time, nt.Valid = v, true
+	case []byte:
+		nt.Time, err = parseTs(time.Local, string(v))
+		nt.Valid = err == nil
+	case string:
+		nt.Time, err = parseTs(time.Local, v)
+		nt.Valid = err == nil
+	default:
+		nt.Time, nt.Valid = time.Time{}, false
+		err = fmt.Errorf("pq: cannot scan %T into NullTime", value)
+	}
+	return err
 }
 
 // Value implements the driver Valuer interface.
@@ -120,3 +614,81 @@ func (nt NullTime) Value() (driver.Value, error) {
 	}
 	return nt.Time, nil
 }
+
+// NullFloat4 represents a float4 (real) that may be NULL. Unlike
+// sql.NullFloat64, it holds the value at float4 precision, so that writing
+// back a value read from a real column sends the digits Postgres printed,
+// such as 0.1, rather than those of the float64 it was widened to,
+// 0.10000000149011612.
+type NullFloat4 struct {
+	Float32 float32
+	Valid   bool // Valid is true if Float32 is not NULL
+}
+
+// Scan implements the Scanner interface. Besides float64 values, as real
+// columns are decoded, it accepts the text of a float4.
+func (nf *NullFloat4) Scan(value interface{}) (err error) {
+	switch v := value.(type) {
+	case nil:
+		nf.Float32, nf.Valid = 0, false
+	case float64:
+		nf.Float32, nf.Valid = float32(v), true
+	case []byte:
+		err = nf.scanText(string(v))
+	case string:
+		err = nf.scanText(v)
+	default:
+		err = fmt.Errorf("pq: cannot scan %T into NullFloat4", value)
+	}
+	return err
+}
+
+func (nf *NullFloat4) scanText(s string) error {
+	f, err := strconv.ParseFloat(s, 32)
+	if err != nil {
+		nf.Float32, nf.Valid = 0, false
+		return fmt.Errorf("pq: %s", err)
+	}
+	nf.Float32, nf.Valid = float32(f), true
+	return nil
+}
+
+// Value implements the driver Valuer interface. The value is sent as
+// float32 parameters are.
+func (nf NullFloat4) Value() (driver.Value, error) {
+	if !nf.Valid {
+		return nil, nil
+	}
+	return string(encodeFloat(&parameterStatus{}, float64(nf.Float32), 32)), nil
+}
+
+// NullBytea represents a bytea value that may be NULL.
+type NullBytea struct {
+	Bytes []byte
+	Valid bool // Valid is true if Bytes is not NULL
+}
+
+// Scan implements the Scanner interface. The scanned bytes are copied, so
+// they remain valid after the next call to Rows.Next.
+func (nb *NullBytea) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		nb.Bytes, nb.Valid = nil, false
+	case []byte:
+		nb.Bytes, nb.Valid = append(make([]byte, 0, len(v)), v...), true
+	case string:
+		nb.Bytes, nb.Valid = []byte(v), true
+	default:
+		nb.Bytes, nb.Valid = nil, false
+		return fmt.Errorf("pq: cannot scan %T into NullBytea", value)
+	}
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (nb NullBytea) Value() (driver.Value, error) {
+	if !nb.Valid {
+		return nil, nil
+	}
+	return nb.Bytes, nil
+}
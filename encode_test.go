@@ -1,11 +1,38 @@
 package pq
 
 import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/xml"
 	"fmt"
+	"github.com/lib/pq/oid"
+	"math"
+	"net"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
 
+// mustDecode decodes s, failing the test if it cannot be decoded.
+func mustDecode(t *testing.T, ps *parameterStatus, s []byte, typ oid.Oid, f format) interface{} {
+	v, err := decode(ps, s, typ, f)
+	if err != nil {
+		t.Fatalf("decoding %q as type %d: %v", s, typ, err)
+	}
+	return v
+}
+
+// mustEncode encodes x, failing the test if it cannot be encoded.
+func mustEncode(t *testing.T, x interface{}, typ oid.Oid) []byte {
+	b, err := encode(&parameterStatus{}, x, typ)
+	if err != nil {
+		t.Fatalf("encoding %#v as type %d: %v", x, typ, err)
+	}
+	return b
+}
+
 func TestScanTimestamp(t *testing.T) {
 	var nt NullTime
 	tn := time.Now()
@@ -26,6 +53,136 @@ func TestScanNilTimestamp(t *testing.T) {
 	}
 }
 
+func TestScanTimestampText(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  time.Time
+	}{
+		{"2012-11-06", time.Date(2012, 11, 6, 0, 0, 0, 0, time.UTC)},
+		{"2012-11-06 10:23:42.5", time.Date(2012, 11, 6, 10, 23, 42, 500000000, time.UTC)},
+		{"2012-11-06 10:23:42-07", time.Date(2012, 11, 6, 17, 23, 42, 0, time.UTC)},
+		{"2012-11-06 10:23:42+05:30", time.Date(2012, 11, 6, 4, 53, 42, 0, time.UTC)},
+		{"infinity", InfinityTime},
+	} {
+		for _, value := range []interface{}{tt.input, []byte(tt.input)} {
+			var nt NullTime
+			if err := nt.Scan(value); err != nil {
+				t.Errorf("%q: unexpected error: %v", tt.input, err)
+				continue
+			}
+			if !nt.Valid || !nt.Time.Equal(tt.want) {
+				t.Errorf("%q: expected %v, got %v (valid: %v)", tt.input, tt.want, nt.Time, nt.Valid)
+			}
+		}
+	}
+}
+
+func TestScanTimestampError(t *testing.T) {
+	for _, value := range []interface{}{"not a timestamp", []byte(""), int64(1), 1.5} {
+		nt := NullTime{Valid: true}
+		if err := nt.Scan(value); err == nil {
+			t.Errorf("%#v: expected an error", value)
+		}
+		if nt.Valid {
+			t.Errorf("%#v: expected Valid=false", value)
+		}
+	}
+}
+
+func TestNullFloat4(t *testing.T) {
+	var nf NullFloat4
+	if err := nf.Scan(float64(float32(0.1))); err != nil {
+		t.Fatal(err)
+	}
+	if !nf.Valid || nf.Float32 != 0.1 {
+		t.Errorf("unexpected %+v", nf)
+	}
+	if v, _ := nf.Value(); v != "0.1" {
+		t.Errorf("expected the float4 digits 0.1, got %#v", v)
+	}
+
+	nf = NullFloat4{}
+	if err := nf.Scan([]byte("0.1")); err != nil {
+		t.Fatal(err)
+	}
+	if nf.Float32 != 0.1 {
+		t.Errorf("expected the float4 0.1, got %v", nf.Float32)
+	}
+
+	if err := nf.Scan(nil); err != nil || nf.Valid {
+		t.Errorf("expected NULL, got %+v, %v", nf, err)
+	}
+	if v, _ := nf.Value(); v != nil {
+		t.Errorf("expected nil, got %#v", v)
+	}
+	if err := nf.Scan("x"); err == nil || nf.Valid {
+		t.Errorf("expected an error, got %+v", nf)
+	}
+	if err := nf.Scan(float32(0.1)); err == nil {
+		t.Error("expected an error for a float32")
+	}
+}
+
+func TestNullFloat4RoundTrip(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var nf NullFloat4
+	err := db.QueryRow("SELECT 0.1::real").Scan(&nf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s string
+	err = db.QueryRow("SELECT $1::text", nf).Scan(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "0.1" {
+		t.Errorf("expected 0.1, got %q", s)
+	}
+}
+
+func TestScanBytea(t *testing.T) {
+	var nb NullBytea
+	b := []byte("abc")
+	nb.Scan(b)
+	if !nb.Valid {
+		t.Errorf("Expected Valid=true")
+	}
+	b[0] = 'x'
+	if string(nb.Bytes) != "abc" {
+		t.Errorf("Bytes value mismatch; expected a copy")
+	}
+
+	nb.Scan([]byte{})
+	if !nb.Valid || nb.Bytes == nil {
+		t.Errorf("Expected an empty, valid value")
+	}
+}
+
+func TestScanNilBytea(t *testing.T) {
+	var nb NullBytea
+	nb.Scan(nil)
+	if nb.Valid {
+		t.Errorf("Expected Valid=false")
+	}
+
+	v, _ := nb.Value()
+	if v != nil {
+		t.Errorf("Expected a nil Value")
+	}
+}
+
+func TestScanInvalidBytea(t *testing.T) {
+	nb := NullBytea{Bytes: []byte("abc"), Valid: true}
+	if err := nb.Scan(int64(1)); err == nil {
+		t.Errorf("Expected an error")
+	}
+	if nb.Valid {
+		t.Errorf("Expected Valid=false")
+	}
+}
+
 func TestTimestampWithTimeZone(t *testing.T) {
 	db := openTestConn(t)
 	defer db.Close()
@@ -162,3 +319,1072 @@ func TestByteToText(t *testing.T) {
 		t.Fatalf("expected %v but got %v", b, result)
 	}
 }
+
+func TestDecodeJSON(t *testing.T) {
+	for _, typ := range []oid.Oid{oid.T_json, oid.T_jsonb} {
+		s := []byte(`{"a": 1}`)
+		got, ok := mustDecode(t, &parameterStatus{}, s, typ, formatText).([]byte)
+		if !ok {
+			t.Fatalf("expected []byte, got %T", got)
+		}
+		s[0] = 'x'
+		if string(got) != `{"a": 1}` {
+			t.Errorf("expected the decoded value to be a copy, got %q", got)
+		}
+	}
+}
+
+func TestDecodeCopiesRawValues(t *testing.T) {
+	for _, tt := range []struct {
+		ps  *parameterStatus
+		typ oid.Oid
+	}{
+		{&parameterStatus{}, oid.T_text},
+		{&parameterStatus{}, oid.T_varchar},
+		{&parameterStatus{}, oid.Oid(99999)},
+		{&parameterStatus{}, oid.T_bpchar},
+		{&parameterStatus{trimBpchar: true}, oid.T_bpchar},
+	} {
+		s := []byte("abc ")
+		got, ok := mustDecode(t, tt.ps, s, tt.typ, formatText).([]byte)
+		if !ok {
+			t.Fatalf("%v: expected []byte, got %T", tt.typ, got)
+		}
+		s[0] = 'x'
+		if len(got) == 0 || got[0] != 'a' {
+			t.Errorf("%v: expected the decoded value to be a copy, got %q", tt.typ, got)
+		}
+	}
+}
+
+func TestDecodeXML(t *testing.T) {
+	s := []byte(`<a>1</a>`)
+	got, ok := mustDecode(t, &parameterStatus{}, s, oid.T_xml, formatText).([]byte)
+	if !ok {
+		t.Fatalf("expected []byte, got %T", got)
+	}
+	s[1] = 'x'
+	if string(got) != `<a>1</a>` {
+		t.Errorf("expected the decoded value to be a copy, got %q", got)
+	}
+}
+
+type xmlNote struct {
+	Body string
+}
+
+func (n xmlNote) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name.Local = "note"
+	return e.EncodeElement(struct {
+		Body string `xml:"body"`
+	}{n.Body}, start)
+}
+
+func TestEncodeXMLMarshaler(t *testing.T) {
+	got := string(mustEncode(t, xmlNote{"a < b"}, oid.T_xml))
+	if want := `<note><body>a &lt; b</body></note>`; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestXMLRoundTrip(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var got []byte
+	err := db.QueryRow("SELECT $1::xml", xmlNote{"hi"}).Scan(&got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `<note><body>hi</body></note>`; string(got) != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestDecodeName(t *testing.T) {
+	got := mustDecode(t, &parameterStatus{}, []byte("pg_class"), oid.T_name, formatText)
+	if got != "pg_class" {
+		t.Errorf("expected pg_class, got %#v", got)
+	}
+}
+
+func TestDecodeNumeric(t *testing.T) {
+	for _, s := range []string{"0", "1.50", "-12345678901234567890.000000000001", "NaN"} {
+		got := mustDecode(t, &parameterStatus{}, []byte(s), oid.T_numeric, formatText)
+		if got != s {
+			t.Errorf("expected %q, got %#v", s, got)
+		}
+	}
+}
+
+func TestEncodeFloat(t *testing.T) {
+	for _, tt := range []struct {
+		x    interface{}
+		want string
+	}{
+		{1.5, "1.5"},
+		{float64(0), "0"},
+		{-0.1, "-0.1"},
+		{1e300, "1e+300"},
+		{1.0 / 3, "0.3333333333333333"},
+		{float32(0.1), "0.1"},
+		{float32(16777216), "1.6777216e+07"},
+	} {
+		if got := string(mustEncode(t, tt.x, oid.T_float8)); got != tt.want {
+			t.Errorf("%#v: expected %q, got %q", tt.x, tt.want, got)
+		}
+	}
+}
+
+func TestEncodeInt(t *testing.T) {
+	for _, tt := range []struct {
+		x    interface{}
+		want string
+	}{
+		{int64(math.MinInt64), "-9223372036854775808"},
+		{int(-1), "-1"},
+		{int32(math.MaxInt32), "2147483647"},
+		{int16(math.MinInt16), "-32768"},
+		{int8(127), "127"},
+	} {
+		if got := string(mustEncode(t, tt.x, oid.T_int8)); got != tt.want {
+			t.Errorf("%#v: expected %q, got %q", tt.x, tt.want, got)
+		}
+	}
+
+	if got := string(mustEncode(t, []int32{1, -2}, oid.T__int4)); got != "{1,-2}" {
+		t.Errorf("expected {1,-2}, got %q", got)
+	}
+	r := Range{Lower: 1, Upper: int16(10), LowerInc: true}
+	if got, err := r.Value(); err != nil || got != `["1","10")` {
+		t.Errorf(`expected ["1","10"), got %#v, %v`, got, err)
+	}
+}
+
+func TestEncodeUint(t *testing.T) {
+	for _, tt := range []struct {
+		x    interface{}
+		typ  oid.Oid
+		want string
+	}{
+		{uint64(math.MaxUint64), oid.T_numeric, "18446744073709551615"},
+		{uint64(math.MaxUint64), oid.T_unknown, "18446744073709551615"},
+		{uint64(math.MaxInt64), oid.T_int8, "9223372036854775807"},
+		{uint(7), oid.T_int2, "7"},
+		{uint32(math.MaxUint32), oid.T_int8, "4294967295"},
+		{uint16(math.MaxUint16), oid.T_int4, "65535"},
+		{uint8(255), oid.T_int2, "255"},
+	} {
+		if got := string(mustEncode(t, tt.x, tt.typ)); got != tt.want {
+			t.Errorf("%#v: expected %q, got %q", tt.x, tt.want, got)
+		}
+	}
+
+	for _, tt := range []struct {
+		x   interface{}
+		typ oid.Oid
+	}{
+		{uint64(math.MaxInt64 + 1), oid.T_int8},
+		{uint32(math.MaxInt32 + 1), oid.T_int4},
+		{uint16(math.MaxInt16 + 1), oid.T_int2},
+	} {
+		if _, err := encode(&parameterStatus{}, tt.x, tt.typ); err == nil {
+			t.Errorf("%#v: expected an error for %v", tt.x, tt.typ)
+		}
+	}
+}
+
+func TestUint64Parameter(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var s string
+	err := db.QueryRow("SELECT $1::numeric::text", uint64(math.MaxUint64)).Scan(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "18446744073709551615" {
+		t.Errorf("expected 18446744073709551615, got %q", s)
+	}
+
+	_, err = db.Exec("SELECT $1::int8", uint64(math.MaxUint64))
+	if err == nil || !strings.Contains(err.Error(), "out of range for int8") {
+		t.Errorf("expected an out of range error, got %v", err)
+	}
+}
+
+func TestEncodeFloatMaxPrecision(t *testing.T) {
+	ps := &parameterStatus{maxFloatPrecision: true}
+	for _, tt := range []struct {
+		x    interface{}
+		want string
+	}{
+		{1.5, "1.5"},
+		{0.1, "0.10000000000000001"},
+		{float32(0.1), "0.100000001"},
+		{math.Inf(1), "Infinity"},
+	} {
+		got, err := encode(ps, tt.x, oid.T_float8)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("%#v: expected %q, got %q", tt.x, tt.want, got)
+		}
+	}
+}
+
+func TestMaxFloatPrecision(t *testing.T) {
+	db := openTestConnConninfo(t, "max_float_precision=yes")
+	defer db.Close()
+
+	var s string
+	err := db.QueryRow("SELECT $1::text", 0.1).Scan(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "0.10000000000000001" {
+		t.Errorf("expected 0.10000000000000001, got %q", s)
+	}
+}
+
+func TestFloatSpecialValues(t *testing.T) {
+	for _, tt := range []struct {
+		f float64
+		s string
+	}{
+		{math.Inf(1), "Infinity"},
+		{math.Inf(-1), "-Infinity"},
+		{math.NaN(), "NaN"},
+	} {
+		if got := string(mustEncode(t, tt.f, oid.T_float8)); got != tt.s {
+			t.Errorf("expected %q, got %q", tt.s, got)
+		}
+		if got := string(mustEncode(t, float32(tt.f), oid.T_float4)); got != tt.s {
+			t.Errorf("expected %q, got %q", tt.s, got)
+		}
+
+		for _, typ := range []oid.Oid{oid.T_float4, oid.T_float8} {
+			got := mustDecode(t, &parameterStatus{}, []byte(tt.s), typ, formatText).(float64)
+			if math.IsNaN(tt.f) {
+				if !math.IsNaN(got) {
+					t.Errorf("expected NaN, got %v", got)
+				}
+			} else if got != tt.f {
+				t.Errorf("expected %v, got %v", tt.f, got)
+			}
+		}
+	}
+}
+
+func TestDecodeInfinity(t *testing.T) {
+	for _, typ := range []oid.Oid{oid.T_date, oid.T_timestamp, oid.T_timestamptz} {
+		if got := mustDecode(t, &parameterStatus{}, []byte("infinity"), typ, formatText); got != InfinityTime {
+			t.Errorf("expected InfinityTime, got %v", got)
+		}
+		if got := mustDecode(t, &parameterStatus{}, []byte("-infinity"), typ, formatText); got != NegInfinityTime {
+			t.Errorf("expected NegInfinityTime, got %v", got)
+		}
+	}
+}
+
+func TestInfinityTimestamp(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var lo, hi time.Time
+	err := db.QueryRow("SELECT '-infinity'::timestamptz, 'infinity'::timestamp").Scan(&lo, &hi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !lo.Equal(NegInfinityTime) {
+		t.Errorf("expected NegInfinityTime, got %v", lo)
+	}
+	if !hi.Equal(InfinityTime) {
+		t.Errorf("expected InfinityTime, got %v", hi)
+	}
+
+	var ok bool
+	err = db.QueryRow("SELECT $1::timestamptz = '-infinity' AND $2::date = 'infinity'",
+		NegInfinityTime, InfinityTime).Scan(&ok)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Error("expected the infinities to be sent as such")
+	}
+}
+
+func TestEncodeInfinity(t *testing.T) {
+	if got := string(mustEncode(t, InfinityTime, oid.T_timestamptz)); got != "infinity" {
+		t.Errorf("expected infinity, got %q", got)
+	}
+	if got := string(mustEncode(t, NegInfinityTime.In(time.FixedZone("", 3600)), oid.T_date)); got != "-infinity" {
+		t.Errorf("expected -infinity, got %q", got)
+	}
+}
+
+func TestDecodeUnsupportedDateStyle(t *testing.T) {
+	ps := &parameterStatus{dateStyle: "German, DMY"}
+	for _, typ := range []oid.Oid{oid.T_date, oid.T_timestamp, oid.T_timestamptz} {
+		if _, err := decode(ps, []byte("06.11.2012"), typ, formatText); err == nil {
+			t.Errorf("expected decoding %d to fail", typ)
+		}
+	}
+}
+
+func TestDateStyle(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("SET LOCAL DateStyle TO 'SQL, DMY'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got time.Time
+	err = tx.QueryRow("SELECT '2012-11-06'::date AS day").Scan(&got)
+	if err == nil {
+		t.Fatal("expected an error for an unsupported DateStyle")
+	}
+	if want := `pq: cannot decode column "day" (date) value "06/11/2012": `; !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("expected the error to start with %q, got %q", want, err)
+	}
+}
+
+func TestDecodeErrorContext(t *testing.T) {
+	_, err := decode(&parameterStatus{}, []byte("12x"), oid.T_int4, formatText)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	err = decodeError("n", oid.T_int4, []byte("12x"), err)
+	want := `pq: cannot decode column "n" (int4) value "12x": strconv.ParseInt: parsing "12x": invalid syntax`
+	if err.Error() != want {
+		t.Errorf("expected %q, got %q", want, err)
+	}
+
+	long := []byte(strings.Repeat("x", 100))
+	err = decodeError("c", oid.Oid(99999), long, fmt.Errorf("bad"))
+	want = fmt.Sprintf(`pq: cannot decode column "c" (type 99999) value %q...: bad`, long[:64])
+	if err.Error() != want {
+		t.Errorf("expected %q, got %q", want, err)
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  string
+	}{
+		{"$0.00", "0.00"},
+		{"$1,234.56", "1234.56"},
+		{"-$1,234.56", "-1234.56"},
+		{"($1.00)", "-1.00"},
+		{"$1,234,567.89", "1234567.89"},
+		{"1.234,56 €", "1234.56"},
+		{"-1 234,56 €", "-1234.56"},
+		{"¥123", "123"},
+		{"¥1,234,567", "1234567"},
+		{"Fr. 12'345.60", "12345.60"},
+		{"BD 1,234.567", "1234.567"},
+		{"BD 0.123", "0.123"},
+	} {
+		got, err := parseMoney(tt.input)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q: expected %q, got %q", tt.input, tt.want, got)
+		}
+	}
+
+	for _, input := range []string{"", "$", "1,234.567,89"} {
+		if _, err := parseMoney(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+
+	// A single separator followed by three digits is a thousands
+	// separator in some locales and a decimal point in others, such as
+	// those of currencies with three decimal places, so the text is left
+	// as it is.
+	for _, input := range []string{"¥1,234", "BD 1.234", "-BD 1.234"} {
+		got, err := parseMoney(input)
+		if err != nil || got != input {
+			t.Errorf("%q: expected the text as it is, got %q, %v", input, got, err)
+		}
+	}
+}
+
+func TestEncodeBinary(t *testing.T) {
+	for _, tt := range []struct {
+		x    interface{}
+		typ  oid.Oid
+		want []byte
+	}{
+		{int64(1), oid.T_int2, []byte{0, 1}},
+		{int64(-2), oid.T_int2, []byte{0xff, 0xfe}},
+		{int64(258), oid.T_int4, []byte{0, 0, 1, 2}},
+		{int64(-1), oid.T_int8, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+	} {
+		if f := paramFormat(tt.x, tt.typ); f != formatBinary {
+			t.Errorf("%v as %d: expected binary format, got %d", tt.x, tt.typ, f)
+			continue
+		}
+		got, err := encodeBinary(tt.x, tt.typ)
+		if err != nil {
+			t.Errorf("%v as %d: unexpected error: %v", tt.x, tt.typ, err)
+			continue
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("%v as %d: expected %x, got %x", tt.x, tt.typ, tt.want, got)
+		}
+	}
+
+	if f := paramFormat("1", oid.T_int4); f != formatText {
+		t.Errorf("expected text format for a string, got %d", f)
+	}
+	if f := paramFormat(int64(1), oid.T_numeric); f != formatText {
+		t.Errorf("expected text format for numeric, got %d", f)
+	}
+}
+
+func TestEncodeBinaryOutOfRange(t *testing.T) {
+	for _, tt := range []struct {
+		x   int64
+		typ oid.Oid
+	}{
+		{math.MaxInt16 + 1, oid.T_int2},
+		{math.MinInt16 - 1, oid.T_int2},
+		{math.MaxInt32 + 1, oid.T_int4},
+	} {
+		if _, err := encodeBinary(tt.x, tt.typ); err == nil {
+			t.Errorf("%d as %d: expected an error", tt.x, tt.typ)
+		}
+	}
+}
+
+func TestDecodeBinary(t *testing.T) {
+	for _, tt := range []struct {
+		s    []byte
+		typ  oid.Oid
+		want interface{}
+	}{
+		{[]byte{0xff, 0xfe}, oid.T_int2, int64(-2)},
+		{[]byte{0, 0, 1, 2}, oid.T_int4, int64(258)},
+		{[]byte{0, 0, 1, 0, 0, 0, 0, 0}, oid.T_int8, int64(1 << 40)},
+		{[]byte{0x3f, 0xc0, 0, 0}, oid.T_float4, float64(1.5)},
+		{[]byte{0xbf, 0xd0, 0, 0, 0, 0, 0, 0}, oid.T_float8, float64(-0.25)},
+	} {
+		got := mustDecode(t, &parameterStatus{}, tt.s, tt.typ, formatBinary)
+		if got != tt.want {
+			t.Errorf("%x as %d: expected %#v, got %#v", tt.s, tt.typ, tt.want, got)
+		}
+	}
+
+	if _, err := decode(&parameterStatus{}, []byte{0, 1}, oid.T_int4, formatBinary); err == nil {
+		t.Error("expected an error for a short int4")
+	}
+}
+
+func TestEncodeBytea(t *testing.T) {
+	for _, tt := range []struct {
+		x    interface{}
+		want string
+	}{
+		{[]byte{}, `\x`},
+		{[]byte{0, 1, 0xab, 0xff}, `\x0001abff`},
+		{"hi", `\x6869`},
+	} {
+		got := string(mustEncode(t, tt.x, oid.T_bytea))
+		if got != tt.want {
+			t.Errorf("%#v: expected %s, got %s", tt.x, tt.want, got)
+		}
+	}
+}
+
+func TestFormatTs(t *testing.T) {
+	est := time.FixedZone("EST", -5*60*60)
+	lmt := time.FixedZone("LMT", 53*60+28)
+	for _, tt := range []struct {
+		t    time.Time
+		want string
+	}{
+		{time.Date(2012, 11, 6, 10, 23, 42, 0, time.UTC), "2012-11-06 10:23:42Z"},
+		{time.Date(2012, 11, 6, 10, 23, 42, 123456000, est), "2012-11-06 10:23:42.123456-05:00:00"},
+		{time.Date(1880, 1, 1, 0, 0, 0, 0, lmt), "1880-01-01 00:00:00+00:53:28"},
+		{time.Date(2012, 11, 6, 10, 23, 42, 123456499, time.UTC), "2012-11-06 10:23:42.123456Z"},
+		{time.Date(2012, 11, 6, 10, 23, 42, 123456500, time.UTC), "2012-11-06 10:23:42.123457Z"},
+		{time.Date(2012, 11, 6, 10, 23, 59, 999999900, time.UTC), "2012-11-06 10:24:00Z"},
+		{time.Date(10, 2, 3, 4, 5, 6, 0, time.UTC), "0010-02-03 04:05:06Z"},
+		{time.Date(0, 2, 29, 0, 0, 0, 0, time.UTC), "0001-02-29 00:00:00Z BC"},
+		{time.Date(-43, 3, 15, 12, 0, 0, 0, time.UTC), "0044-03-15 12:00:00Z BC"},
+	} {
+		if got := string(mustEncode(t, tt.t, oid.T_timestamptz)); got != tt.want {
+			t.Errorf("%v: expected %q, got %q", tt.t, tt.want, got)
+		}
+	}
+}
+
+func TestTimestampMicrosecondRoundTrip(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	in := time.Date(2012, 11, 6, 10, 23, 42, 123456789, time.UTC)
+	var got time.Time
+	err := db.QueryRow("SELECT $1::timestamptz", in).Scan(&got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := in.Round(time.Microsecond); !got.Equal(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestTimestampBCRoundTrip(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var ok bool
+	in := time.Date(-43, 3, 15, 12, 0, 0, 0, time.UTC)
+	err := db.QueryRow("SELECT $1::timestamptz = '0044-03-15 12:00:00+00 BC'", in).Scan(&ok)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Error("expected the BC timestamp to be sent as such")
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		typ   oid.Oid
+	}{
+		{"x", oid.T_int4},
+		{"1.x", oid.T_float8},
+		{"2012-11-06 10:23", oid.T_timestamp},
+		{"10:23", oid.T_time},
+		{`\xzz`, oid.T_bytea},
+	} {
+		v, err := decode(&parameterStatus{}, []byte(tt.input), tt.typ, formatText)
+		if err == nil {
+			t.Errorf("%q as %d: expected an error, got %#v", tt.input, tt.typ, v)
+			continue
+		}
+		if !strings.HasPrefix(err.Error(), "pq: ") {
+			t.Errorf("%q as %d: expected a pq error, got %q", tt.input, tt.typ, err)
+		}
+	}
+}
+
+func TestEncodeError(t *testing.T) {
+	for _, x := range []interface{}{
+		struct{}{},
+		net.IP{1, 2, 3},
+		[]interface{}{1, struct{}{}},
+		Range{Lower: struct{}{}},
+	} {
+		if _, err := encode(&parameterStatus{}, x, oid.T_unknown); err == nil {
+			t.Errorf("%#v: expected an error", x)
+		}
+	}
+}
+
+func TestDecodeErrorInRows(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	// Only ISO dates can be decoded
+	_, err = tx.Exec("SET LOCAL DateStyle = 'German'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d time.Time
+	if err = tx.QueryRow("SELECT '2012-11-06'::date").Scan(&d); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	// The connection is still usable
+	_, err = tx.Exec("SET LOCAL DateStyle = 'ISO'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = tx.QueryRow("SELECT '2012-11-06'::date").Scan(&d); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDecodeEmptyBool(t *testing.T) {
+	if _, err := decode(&parameterStatus{}, []byte{}, oid.T_bool, formatText); err == nil {
+		t.Error("expected an error for an empty bool")
+	}
+}
+
+func TestNumericAsInt64(t *testing.T) {
+	ps := &parameterStatus{numericAsInt64: true}
+	for _, tt := range []struct {
+		input string
+		want  interface{}
+	}{
+		{"42", int64(42)},
+		{"-42", int64(-42)},
+		{"0", int64(0)},
+		{"9223372036854775807", int64(9223372036854775807)},
+		{"9223372036854775808", "9223372036854775808"},
+		{"42.0", "42.0"},
+		{"1.5", "1.5"},
+		{"NaN", "NaN"},
+	} {
+		got := mustDecode(t, ps, []byte(tt.input), oid.T_numeric, formatText)
+		if got != tt.want {
+			t.Errorf("%q: expected %#v, got %#v", tt.input, tt.want, got)
+		}
+	}
+
+	if got := mustDecode(t, &parameterStatus{}, []byte("42"), oid.T_numeric, formatText); got != "42" {
+		t.Errorf("expected the text without the option, got %#v", got)
+	}
+}
+
+func TestNaturalIntWidths(t *testing.T) {
+	ps := &parameterStatus{naturalIntWidths: true}
+	for _, tt := range []struct {
+		input []byte
+		typ   oid.Oid
+		f     format
+		want  interface{}
+	}{
+		{[]byte("-32768"), oid.T_int2, formatText, int16(-32768)},
+		{[]byte("2147483647"), oid.T_int4, formatText, int32(2147483647)},
+		{[]byte("7"), oid.T_int8, formatText, int64(7)},
+		{[]byte{0xff, 0xfe}, oid.T_int2, formatBinary, int16(-2)},
+		{[]byte{0, 0, 1, 0}, oid.T_int4, formatBinary, int32(256)},
+	} {
+		got := mustDecode(t, ps, tt.input, tt.typ, tt.f)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q as %d: expected %#v, got %#v", tt.input, tt.typ, tt.want, got)
+		}
+	}
+
+	if got := mustDecode(t, &parameterStatus{}, []byte("7"), oid.T_int2, formatText); got != int64(7) {
+		t.Errorf("expected int64(7) without the option, got %#v", got)
+	}
+}
+
+func TestNaturalIntWidthsOption(t *testing.T) {
+	db := openTestConnConninfo(t, "natural_int_widths=yes")
+	defer db.Close()
+
+	var a, b, c interface{}
+	err := db.QueryRow("SELECT 1::int2, 2::int4, 3::int8").Scan(&a, &b, &c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != int16(1) || b != int32(2) || c != int64(3) {
+		t.Errorf("unexpected values %#v, %#v, %#v", a, b, c)
+	}
+
+	var small int8
+	if err := db.QueryRow("SELECT 300::int2").Scan(&small); err == nil {
+		t.Errorf("expected an overflow error, got %d", small)
+	}
+}
+
+func TestNumericAsInt64Option(t *testing.T) {
+	db := openTestConnConninfo(t, "numeric_as_int64=yes")
+	defer db.Close()
+
+	var sum interface{}
+	err := db.QueryRow("SELECT sum(x) FROM (VALUES (1), (2)) AS t (x)").Scan(&sum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum != int64(3) {
+		t.Errorf("expected int64(3), got %#v", sum)
+	}
+}
+
+func TestDecodeVoid(t *testing.T) {
+	if got := mustDecode(t, &parameterStatus{}, []byte{}, oid.T_void, formatText); got != "" {
+		t.Errorf("expected \"\", got %#v", got)
+	}
+}
+
+func TestVoidResult(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var v interface{}
+	var s sql.NullString
+	if err := db.QueryRow("SELECT pg_sleep(0), pg_sleep(0)").Scan(&v, &s); err != nil {
+		t.Fatal(err)
+	}
+	if v != "" || !s.Valid || s.String != "" {
+		t.Errorf("expected empty strings, got %#v and %#v", v, s)
+	}
+}
+
+func TestDecodeUnknown(t *testing.T) {
+	s := []byte("hello")
+	got := mustDecode(t, &parameterStatus{}, s, oid.T_unknown, formatText)
+	s[0] = 'j'
+	if got != "hello" {
+		t.Errorf("expected \"hello\", got %#v", got)
+	}
+}
+
+func TestUnknownLiteral(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var v interface{}
+	if err := db.QueryRow("SELECT 'hello'").Scan(&v); err != nil {
+		t.Fatal(err)
+	}
+	if v != "hello" {
+		t.Errorf("expected \"hello\", got %#v", v)
+	}
+}
+
+func TestDecodeRegTypes(t *testing.T) {
+	for _, typ := range []oid.Oid{
+		oid.T_regproc, oid.T_regprocedure, oid.T_regoper, oid.T_regoperator,
+		oid.T_regclass, oid.T_regtype, oid.T_regconfig, oid.T_regdictionary,
+		oid.T_regnamespace, oid.T_regrole,
+	} {
+		s := []byte("pg_catalog.english")
+		got := mustDecode(t, &parameterStatus{}, s, typ, formatText)
+		s[0] = 'x'
+		if got != "pg_catalog.english" {
+			t.Errorf("%d: expected a string, got %#v", typ, got)
+		}
+	}
+}
+
+func TestRegTypesScan(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var class, typ, config interface{}
+	err := db.QueryRow("SELECT 'pg_class'::regclass, 'int4'::regtype, 'english'::regconfig").Scan(&class, &typ, &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		got, want interface{}
+	}{
+		{class, "pg_class"},
+		{typ, "integer"},
+		{config, "english"},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("expected %#v, got %#v", tt.want, tt.got)
+		}
+	}
+}
+
+func TestDecodeChar(t *testing.T) {
+	for input, want := range map[string]string{
+		"":     "\x00",
+		"x":    "x",
+		"\\":   "\\",
+		`\001`: "\x01",
+		`\377`: "\xff",
+	} {
+		got := mustDecode(t, &parameterStatus{}, []byte(input), oid.T_char, formatText)
+		if got != want {
+			t.Errorf("%q: expected %q, got %#v", input, want, got)
+		}
+	}
+
+	for _, input := range []string{"xy", `\400`, `\08`, `\0011`} {
+		if _, err := parseChar([]byte(input)); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestCharScan(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var storage interface{}
+	err := db.QueryRow(`SELECT attstorage FROM pg_attribute WHERE attrelid = 'pg_class'::regclass AND attname = 'relname'`).Scan(&storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if storage != "p" {
+		t.Errorf("expected \"p\", got %#v", storage)
+	}
+}
+
+type testStringer struct{ name string }
+
+func (s testStringer) String() string { return "<" + s.name + ">" }
+
+func TestEncodeStringer(t *testing.T) {
+	if _, err := encode(&parameterStatus{}, testStringer{"a"}, oid.T_text); err == nil {
+		t.Error("expected an error without the option")
+	}
+
+	ps := &parameterStatus{bindStringers: true}
+	for _, tt := range []struct {
+		x    interface{}
+		want []byte
+	}{
+		{testStringer{"a"}, []byte("<a>")},
+		{&testStringer{"b"}, []byte("<b>")},
+		{(*testStringer)(nil), nil},
+		{time.Duration(0), []byte("0")},
+	} {
+		got, err := encode(ps, tt.x, oid.T_text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("%#v: expected %q, got %q", tt.x, tt.want, got)
+		}
+	}
+}
+
+func TestBindStringers(t *testing.T) {
+	db := openTestConnConninfo(t, "bind_stringers=yes")
+	defer db.Close()
+
+	var s string
+	err := db.QueryRow("SELECT $1::text", testStringer{"a"}).Scan(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "<a>" {
+		t.Errorf("expected <a>, got %q", s)
+	}
+}
+
+func TestDecodeBoolSpellings(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  bool
+	}{
+		{"t", true},
+		{"f", false},
+		{"true", true},
+		{"TRUE", true},
+		{"Tr", true},
+		{"yes", true},
+		{"Y", true},
+		{"on", true},
+		{"1", true},
+		{" true ", true},
+		{"false", false},
+		{"FALSE", false},
+		{"fal", false},
+		{"no", false},
+		{"n", false},
+		{"off", false},
+		{"OF", false},
+		{"0", false},
+		{"\tno\n", false},
+	} {
+		got := mustDecode(t, &parameterStatus{}, []byte(tt.input), oid.T_bool, formatText)
+		if got != tt.want {
+			t.Errorf("%q: expected %v, got %#v", tt.input, tt.want, got)
+		}
+	}
+
+	for _, input := range []string{"o", "x", "2", "truee", "yess", "onn", "-1", " ", "t f"} {
+		if _, err := decode(&parameterStatus{}, []byte(input), oid.T_bool, formatText); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+type nilValuer struct{}
+
+func (*nilValuer) Value() (driver.Value, error) {
+	return nil, nil
+}
+
+func TestEncodeNil(t *testing.T) {
+	var (
+		ip  *int64
+		ns  *sql.NullString
+		nv  *nilValuer
+		iv  *Interval
+		any interface{}
+	)
+	for _, x := range []interface{}{
+		nil,
+		ip,
+		ns,
+		nv,
+		iv,
+		&any,
+		sql.NullString{},
+		sql.NullInt64{},
+		[]byte(nil),
+	} {
+		if got := mustEncode(t, x, oid.T_text); got != nil {
+			t.Errorf("%#v: expected nil, got %q", x, got)
+		}
+	}
+
+	n := int64(5)
+	for _, tt := range []struct {
+		x    interface{}
+		want string
+	}{
+		{&n, "5"},
+		{sql.NullString{String: "a", Valid: true}, "a"},
+		{sql.NullInt64{Int64: 7, Valid: true}, "7"},
+		{Interval{Days: 1}, "0 months 1 days 0 microseconds"},
+	} {
+		if got := string(mustEncode(t, tt.x, oid.T_text)); got != tt.want {
+			t.Errorf("%#v: expected %q, got %q", tt.x, tt.want, got)
+		}
+	}
+
+	got := string(mustEncode(t, []sql.NullString{{String: "a", Valid: true}, {}}, oid.T__text))
+	if got != "{a,NULL}" {
+		t.Errorf("expected {a,NULL}, got %s", got)
+	}
+}
+
+func TestDecodeOid(t *testing.T) {
+	for _, typ := range []oid.Oid{oid.T_oid, oid.T_xid, oid.T_cid} {
+		got := mustDecode(t, &parameterStatus{}, []byte("4294967295"), typ, formatText)
+		if got != int64(4294967295) {
+			t.Errorf("%d: expected 4294967295, got %#v", typ, got)
+		}
+	}
+
+	if _, err := decode(&parameterStatus{}, []byte("4294967296"), oid.T_oid, formatText); err == nil {
+		t.Error("expected an error for an out-of-range oid")
+	}
+}
+
+func TestScanOid(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var o int64
+	var name string
+	err := db.QueryRow("SELECT oid, oid::regclass FROM pg_class WHERE relname = 'pg_class'").Scan(&o, &name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o != 1259 || name != "pg_class" {
+		t.Errorf("expected 1259 and pg_class, got %d and %q", o, name)
+	}
+}
+
+func TestDecodeBpchar(t *testing.T) {
+	s := []byte("ab  ")
+	if got := mustDecode(t, &parameterStatus{}, s, oid.T_bpchar, formatText).([]byte); string(got) != "ab  " {
+		t.Errorf("expected the padding to be kept, got %q", got)
+	}
+	ps := &parameterStatus{trimBpchar: true}
+	if got := mustDecode(t, ps, s, oid.T_bpchar, formatText).([]byte); string(got) != "ab" {
+		t.Errorf("expected the padding to be trimmed, got %q", got)
+	}
+}
+
+func TestTrimBpchar(t *testing.T) {
+	db := openTestConnConninfo(t, "trim_bpchar=yes")
+	defer db.Close()
+
+	var s string
+	err := db.QueryRow("SELECT 'ab'::char(4)").Scan(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "ab" {
+		t.Errorf("expected ab, got %q", s)
+	}
+}
+
+func TestFixedTimeZones(t *testing.T) {
+	defer func(loc *time.Location) { time.Local = loc }(time.Local)
+	time.Local = time.FixedZone("CET", 60*60)
+
+	for _, tt := range []struct {
+		input string
+		typ   oid.Oid
+	}{
+		{"2012-11-06 10:23:42+01", oid.T_timestamptz},
+		{"10:23:42+01", oid.T_timetz},
+	} {
+		got := mustDecode(t, &parameterStatus{}, []byte(tt.input), tt.typ, formatText)
+		if loc := got.(time.Time).Location(); loc != time.Local {
+			t.Errorf("%q: expected the local time zone, got %v", tt.input, loc)
+		}
+
+		got = mustDecode(t, &parameterStatus{fixedTimeZones: true}, []byte(tt.input), tt.typ, formatText)
+		name, offset := got.(time.Time).Zone()
+		if name != "" || offset != 60*60 {
+			t.Errorf("%q: expected a fixed zone of +01, got %q %d", tt.input, name, offset)
+		}
+	}
+}
+
+func TestFixedTimeZonesOption(t *testing.T) {
+	db := openTestConnConninfo(t, "fixed_time_zones=yes")
+	defer db.Close()
+
+	var ts time.Time
+	err := db.QueryRow("SELECT '2012-11-06 10:23:42+00'::timestamptz").Scan(&ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts.Location() == time.Local {
+		t.Errorf("expected a fixed zone, got %v", ts.Location())
+	}
+}
+
+func TestForceUTC(t *testing.T) {
+	ps := &parameterStatus{forceUTC: true}
+	for _, tt := range []struct {
+		input string
+		typ   oid.Oid
+		want  time.Time
+	}{
+		{"2012-11-06 10:23:42+01", oid.T_timestamptz, time.Date(2012, 11, 6, 9, 23, 42, 0, time.UTC)},
+		{"2012-11-06 10:23:42-07:30", oid.T_timestamptz, time.Date(2012, 11, 6, 17, 53, 42, 0, time.UTC)},
+		{"2012-11-06 10:23:42", oid.T_timestamp, time.Date(2012, 11, 6, 10, 23, 42, 0, time.UTC)},
+		{"10:23:42+05:30", oid.T_timetz, time.Date(0, 1, 1, 4, 53, 42, 0, time.UTC)},
+		{"infinity", oid.T_timestamptz, InfinityTime},
+	} {
+		got := mustDecode(t, ps, []byte(tt.input), tt.typ, formatText)
+		if got != tt.want {
+			t.Errorf("%q: expected %v, got %v", tt.input, tt.want, got)
+		}
+	}
+}
+
+func TestForceUTCOption(t *testing.T) {
+	db := openTestConnConninfo(t, "force_utc=yes")
+	defer db.Close()
+
+	var ts time.Time
+	err := db.QueryRow("SELECT '2012-11-06 10:23:42+03'::timestamptz").Scan(&ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2012, 11, 6, 7, 23, 42, 0, time.UTC); ts != want {
+		t.Errorf("expected %v, got %v", want, ts)
+	}
+}
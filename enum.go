@@ -0,0 +1,86 @@
+package pq
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq/oid"
+	"reflect"
+)
+
+// Enum describes a user-defined enum type, whose OID varies between
+// databases. Values of enum types are returned as text unless the type is
+// registered with RegisterEnum.
+type Enum struct {
+	Name   string
+	OID    oid.Oid
+	Labels []string // in sort order
+}
+
+// LoadEnum looks up the enum type name, which may be schema-qualified, in
+// the database q, typically a *sql.DB or *sql.Tx. Enum labels added later
+// with ALTER TYPE are only known after loading the enum again.
+func LoadEnum(q interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}, name string) (*Enum, error) {
+	rows, err := q.Query(`SELECT t.oid, e.enumlabel
+		FROM pg_type t JOIN pg_enum e ON e.enumtypid = t.oid
+		WHERE t.oid = $1::regtype
+		ORDER BY e.enumsortorder`, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	e := &Enum{Name: name}
+	for rows.Next() {
+		var label string
+		if err := rows.Scan(&e.OID, &label); err != nil {
+			return nil, err
+		}
+		e.Labels = append(e.Labels, label)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if e.Labels == nil {
+		return nil, fmt.Errorf("pq: %s is not an enum type", name)
+	}
+	return e, nil
+}
+
+// Has reports whether label is one of the labels of e.
+func (e *Enum) Has(label string) bool {
+	for _, l := range e.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterEnum maps the enum e to the Go type of example, which must have
+// string as its underlying type, such as a
+//
+//	type Color string
+//
+// Values of e are then returned as that type, and parameters of that type
+// are checked against the labels of e before they are sent, so that an
+// unknown label fails before reaching the server. Like RegisterEncoder and
+// RegisterDecoder, it panics if either type is already registered.
+func RegisterEnum(example interface{}, e *Enum) {
+	typ := reflect.TypeOf(example)
+	if typ == nil || typ.Kind() != reflect.String {
+		panic(fmt.Sprintf("pq: RegisterEnum: %T does not have string as its underlying type", example))
+	}
+
+	RegisterEncoder(example, func(x interface{}) ([]byte, error) {
+		label := reflect.ValueOf(x).String()
+		if !e.Has(label) {
+			return nil, fmt.Errorf("pq: %q is not a label of enum %s", label, e.Name)
+		}
+		return []byte(label), nil
+	})
+	RegisterDecoder(e.OID, func(s []byte) (interface{}, error) {
+		return reflect.ValueOf(string(s)).Convert(typ).Interface(), nil
+	})
+}
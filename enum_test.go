@@ -0,0 +1,85 @@
+package pq
+
+import (
+	"github.com/lib/pq/oid"
+	"reflect"
+	"testing"
+)
+
+type testMood string
+
+func registerTestMood(t *testing.T, e *Enum) func() {
+	RegisterEnum(testMood(""), e)
+	return func() {
+		registryMu.Lock()
+		delete(encoders, reflect.TypeOf(testMood("")))
+		delete(decoders, e.OID)
+		registryMu.Unlock()
+	}
+}
+
+func TestRegisterEnum(t *testing.T) {
+	e := &Enum{Name: "mood", OID: 99998, Labels: []string{"sad", "ok", "happy"}}
+	if !e.Has("ok") || e.Has("OK") {
+		t.Error("expected Has to match labels exactly")
+	}
+
+	defer registerTestMood(t, e)()
+
+	if got := string(mustEncode(t, testMood("happy"), oid.T_unknown)); got != "happy" {
+		t.Errorf("expected happy, got %q", got)
+	}
+	if _, err := encode(&parameterStatus{}, testMood("grumpy"), oid.T_unknown); err == nil {
+		t.Error("expected an error for an unknown label")
+	}
+	if got := mustDecode(t, &parameterStatus{}, []byte("sad"), e.OID, formatText); got != testMood("sad") {
+		t.Errorf("expected testMood(sad), got %#v", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a type whose underlying type is not string")
+		}
+	}()
+	RegisterEnum(0, e)
+}
+
+func TestLoadEnum(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("CREATE TYPE pq_test_mood AS ENUM ('sad', 'ok', 'happy')")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := LoadEnum(tx, "pq_test_mood")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"sad", "ok", "happy"}; !reflect.DeepEqual(e.Labels, want) {
+		t.Errorf("expected labels %v, got %v", want, e.Labels)
+	}
+	if _, err := LoadEnum(tx, "int4"); err == nil {
+		t.Error("expected an error for a type that is not an enum")
+	}
+
+	defer registerTestMood(t, e)()
+
+	var m testMood
+	err = tx.QueryRow("SELECT $1::pq_test_mood", testMood("happy")).Scan(&m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m != "happy" {
+		t.Errorf("expected happy, got %q", m)
+	}
+	if _, err := tx.Exec("SELECT $1::pq_test_mood", testMood("grumpy")); err == nil {
+		t.Error("expected an error for an unknown label")
+	}
+}
@@ -0,0 +1,444 @@
+package pq
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Point represents a Postgres point.
+//
+// Geometric columns are decoded as text, so that they can be scanned into a
+// string; scan them into a Point, or one of the types below, to parse them.
+type Point struct {
+	X, Y float64
+}
+
+// Scan implements the Scanner interface.
+func (p *Point) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case Point:
+		*p = v
+		return nil
+	case []byte:
+		return p.scanText(string(v))
+	case string:
+		return p.scanText(v)
+	}
+	return fmt.Errorf("pq: cannot scan %T into Point", value)
+}
+
+func (p *Point) scanText(s string) error {
+	v, err := parsePoint(s)
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (p Point) Value() (driver.Value, error) {
+	return string(appendPoint(nil, p)), nil
+}
+
+// Box represents a Postgres box. Postgres rearranges the corners of a box
+// so that the upper right one comes first.
+type Box struct {
+	UpperRight, LowerLeft Point
+}
+
+// Scan implements the Scanner interface.
+func (b *Box) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case Box:
+		*b = v
+		return nil
+	case []byte:
+		return b.scanText(string(v))
+	case string:
+		return b.scanText(v)
+	}
+	return fmt.Errorf("pq: cannot scan %T into Box", value)
+}
+
+func (b *Box) scanText(s string) error {
+	v, err := parseBox(s)
+	if err != nil {
+		return err
+	}
+	*b = v
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (b Box) Value() (driver.Value, error) {
+	buf := appendPoint(nil, b.UpperRight)
+	buf = append(buf, ',')
+	return string(appendPoint(buf, b.LowerLeft)), nil
+}
+
+// Line represents a Postgres line, the infinite line Ax + By + C = 0.
+type Line struct {
+	A, B, C float64
+}
+
+// Scan implements the Scanner interface.
+func (l *Line) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case Line:
+		*l = v
+		return nil
+	case []byte:
+		return l.scanText(string(v))
+	case string:
+		return l.scanText(v)
+	}
+	return fmt.Errorf("pq: cannot scan %T into Line", value)
+}
+
+func (l *Line) scanText(s string) error {
+	v, err := parseLine(s)
+	if err != nil {
+		return err
+	}
+	*l = v
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (l Line) Value() (driver.Value, error) {
+	b := []byte{'{'}
+	b = strconv.AppendFloat(b, l.A, 'g', -1, 64)
+	b = append(b, ',')
+	b = strconv.AppendFloat(b, l.B, 'g', -1, 64)
+	b = append(b, ',')
+	b = strconv.AppendFloat(b, l.C, 'g', -1, 64)
+	return string(append(b, '}')), nil
+}
+
+// LSeg represents a Postgres lseg, a line segment.
+type LSeg struct {
+	P1, P2 Point
+}
+
+// Scan implements the Scanner interface.
+func (l *LSeg) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case LSeg:
+		*l = v
+		return nil
+	case []byte:
+		return l.scanText(string(v))
+	case string:
+		return l.scanText(v)
+	}
+	return fmt.Errorf("pq: cannot scan %T into LSeg", value)
+}
+
+func (l *LSeg) scanText(s string) error {
+	v, err := parseLSeg(s)
+	if err != nil {
+		return err
+	}
+	*l = v
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (l LSeg) Value() (driver.Value, error) {
+	b := appendPoint([]byte{'['}, l.P1)
+	b = append(b, ',')
+	b = appendPoint(b, l.P2)
+	return string(append(b, ']')), nil
+}
+
+// Circle represents a Postgres circle.
+type Circle struct {
+	Center Point
+	Radius float64
+}
+
+// Scan implements the Scanner interface.
+func (c *Circle) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case Circle:
+		*c = v
+		return nil
+	case []byte:
+		return c.scanText(string(v))
+	case string:
+		return c.scanText(v)
+	}
+	return fmt.Errorf("pq: cannot scan %T into Circle", value)
+}
+
+func (c *Circle) scanText(s string) error {
+	v, err := parseCircle(s)
+	if err != nil {
+		return err
+	}
+	*c = v
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (c Circle) Value() (driver.Value, error) {
+	b := appendPoint([]byte{'<'}, c.Center)
+	b = append(b, ',')
+	b = strconv.AppendFloat(b, c.Radius, 'g', -1, 64)
+	return string(append(b, '>')), nil
+}
+
+// Path represents a Postgres path, which is closed, like a polygon, or
+// open.
+type Path struct {
+	Points []Point
+	Closed bool
+}
+
+// Scan implements the Scanner interface.
+func (p *Path) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case Path:
+		*p = v
+		return nil
+	case []byte:
+		return p.scanText(string(v))
+	case string:
+		return p.scanText(v)
+	}
+	return fmt.Errorf("pq: cannot scan %T into Path", value)
+}
+
+func (p *Path) scanText(s string) error {
+	v, err := parsePath(s)
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
+
+// Value implements the driver Valuer interface. Closed paths are written
+// in parentheses and open ones in square brackets.
+func (p Path) Value() (driver.Value, error) {
+	if len(p.Points) == 0 {
+		return nil, fmt.Errorf("pq: cannot encode a path without points")
+	}
+	start, end := byte('['), byte(']')
+	if p.Closed {
+		start, end = '(', ')'
+	}
+	b := []byte{start}
+	for i, pt := range p.Points {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendPoint(b, pt)
+	}
+	return string(append(b, end)), nil
+}
+
+// Polygon represents a Postgres polygon, by its vertices. A nil Polygon is
+// NULL.
+type Polygon []Point
+
+// Scan implements the Scanner interface.
+func (p *Polygon) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*p = nil
+		return nil
+	case Polygon:
+		*p = v
+		return nil
+	case []byte:
+		return p.scanText(string(v))
+	case string:
+		return p.scanText(v)
+	}
+	return fmt.Errorf("pq: cannot scan %T into Polygon", value)
+}
+
+func (p *Polygon) scanText(s string) error {
+	v, err := parsePolygon(s)
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (p Polygon) Value() (driver.Value, error) {
+	if p == nil {
+		return nil, nil
+	}
+	if len(p) == 0 {
+		return nil, fmt.Errorf("pq: cannot encode a polygon without vertices")
+	}
+	b := []byte{'('}
+	for i, pt := range p {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendPoint(b, pt)
+	}
+	return string(append(b, ')')), nil
+}
+
+// parsePoint parses a point such as "(1.5,-2)".
+func parsePoint(s string) (Point, error) {
+	pts, ok := parsePoints(s)
+	if !ok || len(pts) != 1 {
+		return Point{}, fmt.Errorf("pq: unable to parse point %q", s)
+	}
+	return pts[0], nil
+}
+
+// parseBox parses a box such as "(1,1),(0,0)".
+func parseBox(s string) (Box, error) {
+	pts, ok := parsePoints(s)
+	if !ok || len(pts) != 2 {
+		return Box{}, fmt.Errorf("pq: unable to parse box %q", s)
+	}
+	return Box{pts[0], pts[1]}, nil
+}
+
+// parseLine parses a line such as "{1,-1,0}".
+func parseLine(s string) (Line, error) {
+	fail := func() (Line, error) {
+		return Line{}, fmt.Errorf("pq: unable to parse line %q", s)
+	}
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return fail()
+	}
+	coef := strings.Split(s[1:len(s)-1], ",")
+	if len(coef) != 3 {
+		return fail()
+	}
+	var abc [3]float64
+	for i, c := range coef {
+		var err error
+		if abc[i], err = strconv.ParseFloat(c, 64); err != nil {
+			return fail()
+		}
+	}
+	return Line{abc[0], abc[1], abc[2]}, nil
+}
+
+// parseLSeg parses a line segment such as "[(0,0),(1,1)]".
+func parseLSeg(s string) (LSeg, error) {
+	if len(s) < 2 || s[0] != '[' || s[len(s)-1] != ']' {
+		return LSeg{}, fmt.Errorf("pq: unable to parse lseg %q", s)
+	}
+	pts, ok := parsePoints(s[1 : len(s)-1])
+	if !ok || len(pts) != 2 {
+		return LSeg{}, fmt.Errorf("pq: unable to parse lseg %q", s)
+	}
+	return LSeg{pts[0], pts[1]}, nil
+}
+
+// parseCircle parses a circle such as "<(1,2),3>".
+func parseCircle(s string) (Circle, error) {
+	fail := func() (Circle, error) {
+		return Circle{}, fmt.Errorf("pq: unable to parse circle %q", s)
+	}
+	if len(s) < 2 || s[0] != '<' || s[len(s)-1] != '>' {
+		return fail()
+	}
+	s = s[1 : len(s)-1]
+	comma := strings.LastIndexByte(s, ',')
+	if comma < 0 {
+		return fail()
+	}
+	pts, ok := parsePoints(s[:comma])
+	if !ok || len(pts) != 1 {
+		return fail()
+	}
+	r, err := strconv.ParseFloat(s[comma+1:], 64)
+	if err != nil {
+		return fail()
+	}
+	return Circle{pts[0], r}, nil
+}
+
+// parsePath parses a closed path such as "((0,0),(1,1),(1,0))", or an open
+// one such as "[(0,0),(1,1)]".
+func parsePath(s string) (Path, error) {
+	if len(s) < 2 {
+		return Path{}, fmt.Errorf("pq: unable to parse path %q", s)
+	}
+	var closed bool
+	switch s[0:1] + s[len(s)-1:] {
+	case "()":
+		closed = true
+	case "[]":
+	default:
+		return Path{}, fmt.Errorf("pq: unable to parse path %q", s)
+	}
+	pts, ok := parsePoints(s[1 : len(s)-1])
+	if !ok {
+		return Path{}, fmt.Errorf("pq: unable to parse path %q", s)
+	}
+	return Path{pts, closed}, nil
+}
+
+// parsePolygon parses a polygon such as "((0,0),(1,1),(1,0))".
+func parsePolygon(s string) (Polygon, error) {
+	if len(s) < 2 || s[0] != '(' || s[len(s)-1] != ')' {
+		return nil, fmt.Errorf("pq: unable to parse polygon %q", s)
+	}
+	pts, ok := parsePoints(s[1 : len(s)-1])
+	if !ok {
+		return nil, fmt.Errorf("pq: unable to parse polygon %q", s)
+	}
+	return Polygon(pts), nil
+}
+
+// parsePoints parses a comma-separated list of points, each of the form
+// "(x,y)".
+func parsePoints(s string) (pts []Point, ok bool) {
+	for {
+		if !strings.HasPrefix(s, "(") {
+			return nil, false
+		}
+		end := strings.IndexByte(s, ')')
+		if end < 0 {
+			return nil, false
+		}
+		comma := strings.IndexByte(s[:end], ',')
+		if comma < 0 {
+			return nil, false
+		}
+		x, err := strconv.ParseFloat(s[1:comma], 64)
+		if err != nil {
+			return nil, false
+		}
+		y, err := strconv.ParseFloat(s[comma+1:end], 64)
+		if err != nil {
+			return nil, false
+		}
+		pts = append(pts, Point{x, y})
+
+		s = s[end+1:]
+		if s == "" {
+			return pts, true
+		}
+		if s[0] != ',' {
+			return nil, false
+		}
+		s = s[1:]
+	}
+}
+
+// appendPoint appends the text form of p to b.
+func appendPoint(b []byte, p Point) []byte {
+	b = append(b, '(')
+	b = strconv.AppendFloat(b, p.X, 'g', -1, 64)
+	b = append(b, ',')
+	b = strconv.AppendFloat(b, p.Y, 'g', -1, 64)
+	return append(b, ')')
+}
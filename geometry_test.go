@@ -0,0 +1,295 @@
+package pq
+
+import (
+	"github.com/lib/pq/oid"
+	"reflect"
+	"testing"
+)
+
+func TestParsePoint(t *testing.T) {
+	var got Point
+	if err := got.Scan([]byte("(1.5,-2)")); err != nil {
+		t.Fatal(err)
+	}
+	if got != (Point{1.5, -2}) {
+		t.Errorf("unexpected point %#v", got)
+	}
+
+	for _, input := range []string{"", "(1.5,2", "1.5,2", "(1.5)", "(1.5,x)", "(1,2),(3,4)", "(1,2)x"} {
+		if _, err := parsePoint(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestParseBox(t *testing.T) {
+	var got Box
+	if err := got.Scan([]byte("(3,4.5),(-1,0)")); err != nil {
+		t.Fatal(err)
+	}
+	if got != (Box{Point{3, 4.5}, Point{-1, 0}}) {
+		t.Errorf("unexpected box %#v", got)
+	}
+
+	for _, input := range []string{"", "(1,2)", "(1,2),(3,4),(5,6)", "(1,2);(3,4)", "(1,2),"} {
+		if _, err := parseBox(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestParseLine(t *testing.T) {
+	var got Line
+	if err := got.Scan([]byte("{1,-1.5,0}")); err != nil {
+		t.Fatal(err)
+	}
+	if got != (Line{1, -1.5, 0}) {
+		t.Errorf("unexpected line %#v", got)
+	}
+
+	for _, input := range []string{"", "{}", "{1,2}", "{1,2,3,4}", "(1,2,3)", "{1,x,3}", "{1,2,3"} {
+		if _, err := parseLine(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestParseLSeg(t *testing.T) {
+	var got LSeg
+	if err := got.Scan([]byte("[(0,0),(1,2.5)]")); err != nil {
+		t.Fatal(err)
+	}
+	if got != (LSeg{Point{0, 0}, Point{1, 2.5}}) {
+		t.Errorf("unexpected lseg %#v", got)
+	}
+
+	for _, input := range []string{"", "[]", "[(0,0)]", "(0,0),(1,1)", "[(0,0),(1,1),(2,2)]", "[(0,0),(1,1)"} {
+		if _, err := parseLSeg(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestParseCircle(t *testing.T) {
+	var got Circle
+	if err := got.Scan([]byte("<(1,-2),3.5>")); err != nil {
+		t.Fatal(err)
+	}
+	if got != (Circle{Point{1, -2}, 3.5}) {
+		t.Errorf("unexpected circle %#v", got)
+	}
+
+	for _, input := range []string{"", "<>", "<(1,2)>", "<(1,2),>", "(1,2),3", "<(1,2),(3,4)>", "<(1,2),3"} {
+		if _, err := parseCircle(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  Path
+	}{
+		{"((0,0),(1,1),(1,0))", Path{[]Point{{0, 0}, {1, 1}, {1, 0}}, true}},
+		{"[(0,0),(1.5,1)]", Path{[]Point{{0, 0}, {1.5, 1}}, false}},
+		{"((0,0))", Path{[]Point{{0, 0}}, true}},
+	} {
+		var got Path
+		if err := got.Scan([]byte(tt.input)); err != nil {
+			t.Errorf("%q: %s", tt.input, err)
+		} else if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: expected %#v, got %#v", tt.input, tt.want, got)
+		}
+		if v, _ := tt.want.Value(); v != tt.input {
+			t.Errorf("%#v: expected %q, got %#v", tt.want, tt.input, v)
+		}
+	}
+
+	for _, input := range []string{"", "()", "[]", "((0,0)]", "[(0,0))", "(0,0)", "[(0,0),]"} {
+		if _, err := parsePath(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+	if _, err := (Path{Closed: true}).Value(); err == nil {
+		t.Error("expected an error encoding a path without points")
+	}
+}
+
+func TestParsePolygon(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  Polygon
+	}{
+		{"((0,0),(1,1),(1,0))", Polygon{{0, 0}, {1, 1}, {1, 0}}},
+		{"((-1.5,2.25))", Polygon{{-1.5, 2.25}}},
+		{"((1e-05,-3e+20),(0,0))", Polygon{{1e-5, -3e20}, {0, 0}}},
+	} {
+		var got Polygon
+		if err := got.Scan([]byte(tt.input)); err != nil {
+			t.Errorf("%q: %s", tt.input, err)
+		} else if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: expected %#v, got %#v", tt.input, tt.want, got)
+		}
+		if v, _ := tt.want.Value(); v != tt.input {
+			t.Errorf("%#v: expected %q, got %#v", tt.want, tt.input, v)
+		}
+	}
+
+	for _, input := range []string{"", "()", "(0,0)", "[(0,0)]", "((0,0),(1,1)", "((0,0),)"} {
+		if _, err := parsePolygon(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+	if v, err := Polygon(nil).Value(); v != nil || err != nil {
+		t.Errorf("expected a nil Polygon to be NULL, got %#v, %v", v, err)
+	}
+	if _, err := (Polygon{}).Value(); err == nil {
+		t.Error("expected an error encoding a polygon without vertices")
+	}
+}
+
+func TestDecodeGeometryText(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		typ   oid.Oid
+	}{
+		{"(1.5,-2)", oid.T_point},
+		{"(3,4.5),(-1,0)", oid.T_box},
+		{"{1,-1.5,0}", oid.T_line},
+		{"[(0,0),(1,2.5)]", oid.T_lseg},
+		{"<(1,-2),3.5>", oid.T_circle},
+		{"((0,0),(1,1),(1,0))", oid.T_path},
+		{"[(0,0),(1.5,1)]", oid.T_path},
+		{"((-1.5,2.25))", oid.T_polygon},
+	} {
+		got := mustDecode(t, &parameterStatus{}, []byte(tt.input), tt.typ, formatText)
+		if b, ok := got.([]byte); !ok || string(b) != tt.input {
+			t.Errorf("%q: expected the text of the value, got %#v", tt.input, got)
+		}
+	}
+}
+
+func TestGeometryScanString(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	for _, tt := range []struct {
+		query string
+		want  string
+	}{
+		{"SELECT '(1.5,-2)'::point", "(1.5,-2)"},
+		{"SELECT '(0,0),(2,3)'::box", "(2,3),(0,0)"},
+		{"SELECT '{1,-1,0}'::line", "{1,-1,0}"},
+		{"SELECT '[(0,0),(1,1)]'::lseg", "[(0,0),(1,1)]"},
+		{"SELECT '<(1,2),3>'::circle", "<(1,2),3>"},
+		{"SELECT '[(0,0),(1,1)]'::path", "[(0,0),(1,1)]"},
+		{"SELECT '((0,0),(1,1),(1,0))'::polygon", "((0,0),(1,1),(1,0))"},
+	} {
+		var s string
+		if err := db.QueryRow(tt.query).Scan(&s); err != nil {
+			t.Fatalf("%s: %s", tt.query, err)
+		}
+		if s != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.query, tt.want, s)
+		}
+	}
+}
+
+func TestGeometryScanValue(t *testing.T) {
+	var p Point
+	if err := p.Scan([]byte("(1,2)")); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := p.Value(); v != "(1,2)" {
+		t.Errorf("unexpected point value %#v", v)
+	}
+	if err := p.Scan(nil); err == nil {
+		t.Error("expected an error scanning nil into a Point")
+	}
+
+	var b Box
+	if err := b.Scan("(1,1),(0.5,0)"); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := b.Value(); v != "(1,1),(0.5,0)" {
+		t.Errorf("unexpected box value %#v", v)
+	}
+
+	var l Line
+	if err := l.Scan([]byte("{1,-1,0.5}")); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := l.Value(); v != "{1,-1,0.5}" {
+		t.Errorf("unexpected line value %#v", v)
+	}
+
+	var ls LSeg
+	if err := ls.Scan("[(1,2),(3,4)]"); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := ls.Value(); v != "[(1,2),(3,4)]" {
+		t.Errorf("unexpected lseg value %#v", v)
+	}
+}
+
+func TestGeometryRoundTrip(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var p Point
+	var b Box
+	err := db.QueryRow("SELECT $1::point, $2::box",
+		Point{1.5, -2}, Box{Point{0, 0}, Point{2, 3}}).Scan(&p, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p != (Point{1.5, -2}) {
+		t.Errorf("unexpected point %#v", p)
+	}
+	if b != (Box{Point{2, 3}, Point{0, 0}}) {
+		t.Errorf("unexpected box %#v", b)
+	}
+
+	var l Line
+	var ls LSeg
+	err = db.QueryRow("SELECT $1::line, $2::lseg",
+		Line{1, -1, 0}, LSeg{Point{0, 0}, Point{1, 1}}).Scan(&l, &ls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l != (Line{1, -1, 0}) {
+		t.Errorf("unexpected line %#v", l)
+	}
+	if ls != (LSeg{Point{0, 0}, Point{1, 1}}) {
+		t.Errorf("unexpected lseg %#v", ls)
+	}
+
+	var c Circle
+	var open, closed Path
+	err = db.QueryRow("SELECT $1::circle, $2::path, $3::path",
+		Circle{Point{1, 2}, 3},
+		Path{[]Point{{0, 0}, {1, 1}}, false},
+		Path{[]Point{{0, 0}, {1, 1}, {1, 0}}, true}).Scan(&c, &open, &closed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c != (Circle{Point{1, 2}, 3}) {
+		t.Errorf("unexpected circle %#v", c)
+	}
+	if want := (Path{[]Point{{0, 0}, {1, 1}}, false}); !reflect.DeepEqual(open, want) {
+		t.Errorf("expected %#v, got %#v", want, open)
+	}
+	if want := (Path{[]Point{{0, 0}, {1, 1}, {1, 0}}, true}); !reflect.DeepEqual(closed, want) {
+		t.Errorf("expected %#v, got %#v", want, closed)
+	}
+
+	var poly Polygon
+	err = db.QueryRow("SELECT $1::polygon", Polygon{{-1, 0.5}}).Scan(&poly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Polygon{{-1, 0.5}}); !reflect.DeepEqual(poly, want) {
+		t.Errorf("expected %#v, got %#v", want, poly)
+	}
+}
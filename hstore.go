@@ -0,0 +1,178 @@
+package pq
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Hstore represents a value of the hstore extension type. Since hstore is
+// not built in, its type OID varies between databases, and its values are
+// returned as text; scan them into an Hstore instead. A nil Hstore is NULL.
+type Hstore map[string]sql.NullString
+
+// Scan implements the Scanner interface.
+func (h *Hstore) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		*h = nil
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("pq: cannot scan %T into Hstore", value)
+	}
+
+	m, err := parseHstore(s)
+	if err != nil {
+		return err
+	}
+	*h = m
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (h Hstore) Value() (driver.Value, error) {
+	if h == nil {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b []byte
+	for i, k := range keys {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = appendHstoreString(b, k)
+		b = append(b, "=>"...)
+		if v := h[k]; v.Valid {
+			b = appendHstoreString(b, v.String)
+		} else {
+			b = append(b, "NULL"...)
+		}
+	}
+	return string(b), nil
+}
+
+// appendHstoreString appends s to b as a quoted hstore key or value.
+func appendHstoreString(b []byte, s string) []byte {
+	b = append(b, '"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			b = append(b, '\\')
+		}
+		b = append(b, s[i])
+	}
+	return append(b, '"')
+}
+
+// hstoreParser holds the state of parseHstore.
+type hstoreParser struct {
+	s   string
+	pos int
+}
+
+func (p *hstoreParser) skipSpace() {
+	for p.pos < len(p.s) && isArraySpace(p.s[p.pos]) {
+		p.pos++
+	}
+}
+
+// token reads a key or value, either quoted, or unquoted and ending at
+// whitespace, a comma or "=>". It reports whether the token was quoted,
+// since only an unquoted NULL is a null value.
+func (p *hstoreParser) token() (tok string, quoted bool, err error) {
+	if p.pos < len(p.s) && p.s[p.pos] == '"' {
+		var b []byte
+		for p.pos++; p.pos < len(p.s); p.pos++ {
+			switch c := p.s[p.pos]; c {
+			case '"':
+				p.pos++
+				return string(b), true, nil
+			case '\\':
+				p.pos++
+				if p.pos == len(p.s) {
+					return "", false, p.errorf("unexpected end of input")
+				}
+				b = append(b, p.s[p.pos])
+			default:
+				b = append(b, c)
+			}
+		}
+		return "", false, p.errorf("unterminated quoted string")
+	}
+
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if isArraySpace(c) || c == ',' || strings.HasPrefix(p.s[p.pos:], "=>") {
+			break
+		}
+		p.pos++
+	}
+	if p.pos == start {
+		return "", false, p.errorf("expected a key or value")
+	}
+	return p.s[start:p.pos], false, nil
+}
+
+func (p *hstoreParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("pq: unable to parse hstore %q at offset %d: %s",
+		p.s, p.pos, fmt.Sprintf(format, args...))
+}
+
+// parseHstore parses the text form of an hstore, such as
+// `"a"=>"1", "b"=>NULL`.
+func parseHstore(s string) (Hstore, error) {
+	h := make(Hstore)
+	p := &hstoreParser{s: s}
+
+	p.skipSpace()
+	for p.pos < len(p.s) {
+		key, _, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpace()
+		if !strings.HasPrefix(p.s[p.pos:], "=>") {
+			return nil, p.errorf(`expected "=>"`)
+		}
+		p.pos += len("=>")
+		p.skipSpace()
+
+		val, quoted, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+		if !quoted && strings.EqualFold(val, "NULL") {
+			h[key] = sql.NullString{}
+		} else {
+			h[key] = sql.NullString{String: val, Valid: true}
+		}
+
+		p.skipSpace()
+		if p.pos == len(p.s) {
+			break
+		}
+		if p.s[p.pos] != ',' {
+			return nil, p.errorf("expected a comma")
+		}
+		p.pos++
+		p.skipSpace()
+		if p.pos == len(p.s) {
+			return nil, p.errorf("unexpected end of input")
+		}
+	}
+	return h, nil
+}
@@ -0,0 +1,130 @@
+package pq
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func hstoreValue(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: true}
+}
+
+func TestParseHstore(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  Hstore
+	}{
+		{``, Hstore{}},
+		{`"a"=>"1"`, Hstore{"a": hstoreValue("1")}},
+		{`"a"=>"1", "b"=>NULL`, Hstore{"a": hstoreValue("1"), "b": {}}},
+		{`"a"=>"NULL"`, Hstore{"a": hstoreValue("NULL")}},
+		{`"k=>v"=>"x, y", "q\"t"=>"b\\s"`, Hstore{"k=>v": hstoreValue("x, y"), `q"t`: hstoreValue(`b\s`)}},
+		{` a => b ,c=>null `, Hstore{"a": hstoreValue("b"), "c": {}}},
+		{`""=>""`, Hstore{"": hstoreValue("")}},
+	} {
+		got, err := parseHstore(tt.input)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: expected %#v, got %#v", tt.input, tt.want, got)
+		}
+	}
+}
+
+func TestParseHstoreError(t *testing.T) {
+	for _, input := range []string{
+		`"a"`,
+		`"a"=>`,
+		`"a"=`,
+		`"a"=>"1",`,
+		`"a"=>"1" "b"=>"2"`,
+		`"a=>"1"`,
+		`"a"=>"1\`,
+		`=>"1"`,
+	} {
+		if _, err := parseHstore(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestHstoreValue(t *testing.T) {
+	h := Hstore{
+		"b":    {},
+		"a":    hstoreValue("1"),
+		`q"t`:  hstoreValue(`b\s`),
+		"k=>v": hstoreValue(""),
+	}
+	got, err := h.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `"a"=>"1", "b"=>NULL, "k=>v"=>"", "q\"t"=>"b\\s"`
+	if got != want {
+		t.Errorf("expected %s, got %v", want, got)
+	}
+
+	back, err := parseHstore(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, h) {
+		t.Errorf("expected %#v, got %#v", h, back)
+	}
+
+	if got, _ := Hstore(nil).Value(); got != nil {
+		t.Errorf("expected nil for a nil Hstore, got %#v", got)
+	}
+}
+
+func TestHstoreScan(t *testing.T) {
+	var h Hstore
+	if err := h.Scan([]byte(`"a"=>"1"`)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(h, Hstore{"a": hstoreValue("1")}) {
+		t.Errorf("unexpected hstore %#v", h)
+	}
+	if err := h.Scan(nil); err != nil || h != nil {
+		t.Errorf("expected a nil Hstore, got %#v (%v)", h, err)
+	}
+	if err := h.Scan(int64(1)); err == nil {
+		t.Error("expected an error for an integer")
+	}
+}
+
+func TestHstoreRoundTrip(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var exists bool
+	err := db.QueryRow("SELECT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'hstore')").Scan(&exists)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists {
+		t.Skip("the hstore extension is not installed")
+	}
+
+	in := Hstore{"a": hstoreValue("1"), "b": {}, `c"d`: hstoreValue("e, f")}
+	var got Hstore
+	err = db.QueryRow("SELECT $1::hstore", in).Scan(&got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, in) {
+		t.Errorf("expected %#v, got %#v", in, got)
+	}
+
+	var null Hstore
+	err = db.QueryRow("SELECT NULL::hstore").Scan(&null)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if null != nil {
+		t.Errorf("expected a nil Hstore, got %#v", null)
+	}
+}
@@ -0,0 +1,298 @@
+package pq
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Interval represents a Postgres interval. Like Postgres, it keeps months,
+// days and smaller units apart, since neither the length of a month nor
+// that of a day (across a daylight saving time change) is fixed.
+type Interval struct {
+	Months       int32
+	Days         int32
+	Microseconds int64
+}
+
+// Duration approximates the interval as a time.Duration, taking a month to
+// be 30 days and a day to be 24 hours.
+func (iv Interval) Duration() time.Duration {
+	days := int64(iv.Months)*30 + int64(iv.Days)
+	return time.Duration(days)*24*time.Hour + time.Duration(iv.Microseconds)*time.Microsecond
+}
+
+// Scan implements the Scanner interface.
+func (iv *Interval) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case Interval:
+		*iv = v
+		return nil
+	case []byte:
+		return iv.scanText(string(v))
+	case string:
+		return iv.scanText(v)
+	}
+	return fmt.Errorf("pq: cannot scan %T into Interval", value)
+}
+
+// scanText parses s in any of the IntervalStyles parseInterval and
+// parseISOInterval understand. The iso_8601 style is told apart by its
+// leading 'P'.
+func (iv *Interval) scanText(s string) error {
+	parse := parseInterval
+	if strings.HasPrefix(s, "P") {
+		parse = parseISOInterval
+	}
+	v, err := parse(s)
+	if err != nil {
+		return err
+	}
+	*iv = v
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (iv Interval) Value() (driver.Value, error) {
+	return fmt.Sprintf("%d months %d days %d microseconds",
+		iv.Months, iv.Days, iv.Microseconds), nil
+}
+
+// encodeDuration formats d as an interval of seconds, such as
+// "-90.000001500 seconds". Postgres rounds it to the microsecond.
+func encodeDuration(d time.Duration) []byte {
+	b := make([]byte, 0, len("-9223372036.854775808 seconds"))
+	ns := uint64(d)
+	if d < 0 {
+		b = append(b, '-')
+		ns = -ns
+	}
+	b = strconv.AppendUint(b, ns/1e9, 10)
+	b = append(b, '.')
+	frac := strconv.FormatUint(ns%1e9, 10)
+	for i := len(frac); i < 9; i++ {
+		b = append(b, '0')
+	}
+	b = append(b, frac...)
+	return append(b, " seconds"...)
+}
+
+// parseInterval parses an interval in the postgres or postgres_verbose
+// IntervalStyle, such as "1 year 2 mons -3 days +04:05:06.7" or
+// "@ 1 year 2 mons 3 days 4 hours 5 mins 6.7 secs ago".
+func parseInterval(s string) (Interval, error) {
+	var iv Interval
+	fail := func() (Interval, error) {
+		return Interval{}, fmt.Errorf("pq: unable to parse interval %q", s)
+	}
+
+	fields := strings.Fields(s)
+	switch {
+	case len(fields) == 0:
+		return fail()
+	case len(fields) == 2 && fields[0] == "@" && fields[1] == "0":
+		// The verbose style's empty interval
+		return iv, nil
+	}
+
+	ago, empty := false, true
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		switch {
+		case f == "@" && i == 0:
+			continue
+		case f == "ago" && i == len(fields)-1:
+			ago = true
+			continue
+		case strings.IndexByte(f, ':') >= 0:
+			us, ok := parseIntervalTime(f)
+			if !ok {
+				return fail()
+			}
+			iv.Microseconds += us
+			empty = false
+			continue
+		}
+
+		// Everything else is a quantity followed by its unit.
+		if i+1 == len(fields) {
+			return fail()
+		}
+		i++
+		unit := strings.TrimSuffix(fields[i], "s")
+		empty = false
+
+		if unit == "sec" || unit == "second" {
+			us, ok := parseMicroseconds(f)
+			if !ok {
+				return fail()
+			}
+			iv.Microseconds += us
+			continue
+		}
+
+		n, err := strconv.ParseInt(f, 10, 32)
+		if err != nil {
+			return fail()
+		}
+		switch unit {
+		case "year":
+			iv.Months += int32(n) * 12
+		case "mon", "month":
+			iv.Months += int32(n)
+		case "day":
+			iv.Days += int32(n)
+		case "hour":
+			iv.Microseconds += n * int64(time.Hour/time.Microsecond)
+		case "min", "minute":
+			iv.Microseconds += n * int64(time.Minute/time.Microsecond)
+		default:
+			return fail()
+		}
+	}
+
+	if empty {
+		return fail()
+	}
+	if ago {
+		iv.Months, iv.Days, iv.Microseconds = -iv.Months, -iv.Days, -iv.Microseconds
+	}
+	return iv, nil
+}
+
+// parseISOInterval parses an interval in the iso_8601 IntervalStyle, such
+// as "P1Y2M-3DT4H5M6.7S" or "PT0S", where each component may be negative.
+func parseISOInterval(s string) (Interval, error) {
+	var iv Interval
+	fail := func() (Interval, error) {
+		return Interval{}, fmt.Errorf("pq: unable to parse interval %q", s)
+	}
+
+	if len(s) < 2 || s[0] != 'P' {
+		return fail()
+	}
+	rest := s[1:]
+	inTime := false
+	for len(rest) > 0 {
+		if rest[0] == 'T' && !inTime {
+			inTime = true
+			rest = rest[1:]
+			if len(rest) == 0 {
+				return fail()
+			}
+			continue
+		}
+
+		i := strings.IndexAny(rest, "YMWDHS")
+		if i < 1 {
+			return fail()
+		}
+		f, unit := rest[:i], rest[i]
+		rest = rest[i+1:]
+
+		if unit == 'S' {
+			us, ok := parseMicroseconds(f)
+			if !ok || !inTime {
+				return fail()
+			}
+			iv.Microseconds += us
+			continue
+		}
+
+		n, err := strconv.ParseInt(f, 10, 32)
+		if err != nil {
+			return fail()
+		}
+		switch {
+		case unit == 'Y' && !inTime:
+			iv.Months += int32(n) * 12
+		case unit == 'M' && !inTime:
+			iv.Months += int32(n)
+		case unit == 'W' && !inTime:
+			iv.Days += int32(n) * 7
+		case unit == 'D' && !inTime:
+			iv.Days += int32(n)
+		case unit == 'H' && inTime:
+			iv.Microseconds += n * int64(time.Hour/time.Microsecond)
+		case unit == 'M' && inTime:
+			iv.Microseconds += n * int64(time.Minute/time.Microsecond)
+		default:
+			return fail()
+		}
+	}
+	return iv, nil
+}
+
+// parseIntervalTime parses the time part of an interval, [+-]hh:mm:ss[.f],
+// into microseconds.
+func parseIntervalTime(s string) (int64, bool) {
+	neg := false
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, false
+	}
+	h, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	m, err := strconv.ParseUint(parts[1], 10, 8)
+	if err != nil || m > 59 {
+		return 0, false
+	}
+	sec, ok := parseMicroseconds(parts[2])
+	if !ok || sec < 0 {
+		return 0, false
+	}
+
+	us := (int64(h)*60+int64(m))*60*1000000 + sec
+	if neg {
+		us = -us
+	}
+	return us, true
+}
+
+// parseMicroseconds parses a number of seconds with an optional fraction,
+// such as "-6.789", into microseconds.
+func parseMicroseconds(s string) (int64, bool) {
+	neg := false
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	if len(frac) > 6 {
+		return 0, false
+	}
+
+	sec, err := strconv.ParseUint(whole, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	us := int64(sec) * 1000000
+	if frac != "" {
+		f, err := strconv.ParseUint(frac, 10, 32)
+		if err != nil {
+			return 0, false
+		}
+		for i := len(frac); i < 6; i++ {
+			f *= 10
+		}
+		us += int64(f)
+	}
+
+	if neg {
+		us = -us
+	}
+	return us, true
+}
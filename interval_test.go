@@ -0,0 +1,236 @@
+package pq
+
+import (
+	"github.com/lib/pq/oid"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseInterval(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  Interval
+	}{
+		{"00:00:00", Interval{}},
+		{"1 day", Interval{0, 1, 0}},
+		{"2 days 03:00:00", Interval{0, 2, 3 * 3600000000}},
+		{"1 year 2 mons 3 days 04:05:06.789", Interval{14, 3, 14706789000}},
+		{"-1 years -2 mons +3 days -04:05:06", Interval{-14, 3, -14706000000}},
+		{"-00:00:00.000001", Interval{0, 0, -1}},
+		{"100:00:00", Interval{0, 0, 360000000000}},
+		{"@ 0", Interval{}},
+		{"@ 1 min", Interval{0, 0, 60000000}},
+		{"@ 1 year 2 mons 3 days 4 hours 5 mins 6.789 secs", Interval{14, 3, 14706789000}},
+		{"@ 1 day 2 hours ago", Interval{0, -1, -7200000000}},
+		{"@ 1 day -2 hours ago", Interval{0, -1, 7200000000}},
+	} {
+		got, err := parseInterval(tt.input)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q: expected %+v, got %+v", tt.input, tt.want, got)
+		}
+	}
+}
+
+func TestParseIntervalError(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"1",
+		"1 fortnight",
+		"x days",
+		"1:2",
+		"00:60:00",
+		"00:00:00.1234567",
+		"ago",
+	} {
+		if _, err := parseInterval(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestParseISOInterval(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  Interval
+	}{
+		{"PT0S", Interval{}},
+		{"P1D", Interval{0, 1, 0}},
+		{"P2DT3H", Interval{0, 2, 3 * 3600000000}},
+		{"P1Y2M3DT4H5M6.789S", Interval{14, 3, 14706789000}},
+		{"P-1Y-2M3DT-4H-5M-6S", Interval{-14, 3, -14706000000}},
+		{"PT-0.000001S", Interval{0, 0, -1}},
+		{"PT100H", Interval{0, 0, 360000000000}},
+		{"P1W", Interval{0, 7, 0}},
+	} {
+		got, err := parseISOInterval(tt.input)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q: expected %+v, got %+v", tt.input, tt.want, got)
+		}
+	}
+
+	for _, input := range []string{"", "P", "PT", "1D", "P1", "PD", "P1H", "PT1D", "P1.5D", "PT1.1234567S", "P1DT", "PTT1S", "P1X"} {
+		if _, err := parseISOInterval(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestIntervalScanStyles(t *testing.T) {
+	want := Interval{14, -3, 14706000000}
+	for style, input := range map[string]string{
+		"postgres":         "1 year 2 mons -3 days +04:05:06",
+		"postgres_verbose": "@ 1 year 2 mons -3 days 4 hours 5 mins 6 secs",
+		"iso_8601":         "P1Y2M-3DT4H5M6S",
+	} {
+		var iv Interval
+		if err := iv.Scan([]byte(input)); err != nil {
+			t.Errorf("%s: %s", style, err)
+		} else if iv != want {
+			t.Errorf("%s: expected %+v, got %+v", style, want, iv)
+		}
+	}
+
+	// The sql_standard style is ambiguous without knowing the
+	// interval's fields.
+	var iv Interval
+	if err := iv.Scan("+1-2 -3 +4:05:06"); err == nil {
+		t.Error("expected an error for the sql_standard style")
+	}
+}
+
+func TestDecodeIntervalText(t *testing.T) {
+	got := mustDecode(t, &parameterStatus{}, []byte("1 day"), oid.T_interval, formatText)
+	if b, ok := got.([]byte); !ok || string(b) != "1 day" {
+		t.Errorf("expected the text of the interval, got %#v", got)
+	}
+}
+
+func TestIntervalScanString(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var s string
+	err := db.QueryRow("SELECT '2 days 03:00:00'::interval").Scan(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "2 days 03:00:00" {
+		t.Errorf("unexpected interval text %q", s)
+	}
+}
+
+func TestIntervalStyleISO8601(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("SET LOCAL IntervalStyle = iso_8601"); err != nil {
+		t.Fatal(err)
+	}
+	var iv Interval
+	err = tx.QueryRow("SELECT '-1 year 2 days -03:04:05.5'::interval").Scan(&iv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Interval{-12, 2, -11045500000}
+	if iv != want {
+		t.Errorf("expected %+v, got %+v", want, iv)
+	}
+}
+
+func TestIntervalDuration(t *testing.T) {
+	iv := Interval{Months: 1, Days: 2, Microseconds: 3}
+	want := 32*24*time.Hour + 3*time.Microsecond
+	if got := iv.Duration(); got != want {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestIntervalScanValue(t *testing.T) {
+	var iv Interval
+	if err := iv.Scan(mustDecode(t, &parameterStatus{}, []byte("1 mon -2 days 00:00:03"), oid.T_interval, formatText)); err != nil {
+		t.Fatal(err)
+	}
+	if want := (Interval{1, -2, 3000000}); iv != want {
+		t.Fatalf("expected %+v, got %+v", want, iv)
+	}
+
+	v, err := iv.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "1 months -2 days 3000000 microseconds" {
+		t.Errorf("unexpected value %q", v)
+	}
+
+	if err := iv.Scan(nil); err == nil {
+		t.Error("expected an error scanning NULL")
+	}
+}
+
+func TestIntervalRoundTrip(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	want := Interval{Months: -14, Days: 3, Microseconds: 14706789000}
+	var got Interval
+	err := db.QueryRow("SELECT $1::interval", want).Scan(&got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestEncodeDuration(t *testing.T) {
+	for _, tt := range []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0.000000000 seconds"},
+		{90 * time.Millisecond, "0.090000000 seconds"},
+		{-(time.Hour + 1500*time.Nanosecond), "-3600.000001500 seconds"},
+		{math.MinInt64, "-9223372036.854775808 seconds"},
+	} {
+		if got := string(mustEncode(t, tt.d, oid.T_interval)); got != tt.want {
+			t.Errorf("%v: expected %q, got %q", tt.d, tt.want, got)
+		}
+	}
+
+	if got := string(mustEncode(t, time.Second, oid.T_int8)); got != "1000000000" {
+		t.Errorf("expected nanoseconds for an int8, got %q", got)
+	}
+}
+
+func TestDurationParameter(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var iv Interval
+	var n int64
+	err := db.QueryRow("SELECT $1::interval, $2::int8", -(90*time.Minute+1600*time.Nanosecond), time.Second).Scan(&iv, &n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Interval{Microseconds: -5400000002}); iv != want {
+		t.Errorf("expected %#v, got %#v", want, iv)
+	}
+	if n != 1000000000 {
+		t.Errorf("expected 1000000000, got %d", n)
+	}
+}
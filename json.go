@@ -0,0 +1,62 @@
+package pq
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// JSON returns a value that binds v, marshaled with encoding/json, as a json
+// or jsonb parameter, or scans a json or jsonb value into v, which must then
+// be a pointer, by unmarshaling it. For example:
+//
+//	db.Exec("INSERT INTO events (payload) VALUES ($1)", pq.JSON(map[string]interface{}{"id": 7}))
+//
+//	var payload map[string]interface{}
+//	err := db.QueryRow("SELECT payload FROM events").Scan(pq.JSON(&payload))
+//
+// A nil v binds as NULL, and NULL scans into the zero value of what v
+// points to.
+func JSON(v interface{}) interface {
+	driver.Valuer
+	sql.Scanner
+} {
+	return jsonValue{v}
+}
+
+type jsonValue struct {
+	v interface{}
+}
+
+// Value implements the driver Valuer interface.
+func (j jsonValue) Value() (driver.Value, error) {
+	if j.v == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(j.v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements the Scanner interface.
+func (j jsonValue) Scan(src interface{}) error {
+	dv := reflect.ValueOf(j.v)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return fmt.Errorf("pq: cannot scan JSON into %T; a pointer is required", j.v)
+	}
+
+	switch src := src.(type) {
+	case nil:
+		dv.Elem().Set(reflect.Zero(dv.Elem().Type()))
+		return nil
+	case []byte:
+		return json.Unmarshal(src, j.v)
+	case string:
+		return json.Unmarshal([]byte(src), j.v)
+	}
+	return fmt.Errorf("pq: cannot scan %T into JSON", src)
+}
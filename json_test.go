@@ -0,0 +1,83 @@
+package pq
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJSONValue(t *testing.T) {
+	for _, tt := range []struct {
+		v    interface{}
+		want interface{}
+	}{
+		{nil, nil},
+		{map[string]interface{}{"a": 1, "b": []string{"c"}}, `{"a":1,"b":["c"]}`},
+		{"x", `"x"`},
+		{[]int(nil), "null"},
+	} {
+		got, err := JSON(tt.v).Value()
+		if err != nil {
+			t.Errorf("%#v: unexpected error: %v", tt.v, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%#v: expected %#v, got %#v", tt.v, tt.want, got)
+		}
+	}
+
+	if _, err := JSON(make(chan int)).Value(); err == nil {
+		t.Error("expected an error for a value json cannot marshal")
+	}
+}
+
+func TestJSONScan(t *testing.T) {
+	var m map[string]interface{}
+	if err := JSON(&m).Scan([]byte(`{"a": [1, "b"]}`)); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]interface{}{"a": []interface{}{1.0, "b"}}; !reflect.DeepEqual(m, want) {
+		t.Errorf("expected %#v, got %#v", want, m)
+	}
+	if err := JSON(&m).Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if m != nil {
+		t.Errorf("expected nil after scanning NULL, got %#v", m)
+	}
+
+	var s struct{ N int }
+	if err := JSON(&s).Scan(`{"N": 3}`); err != nil {
+		t.Fatal(err)
+	}
+	if s.N != 3 {
+		t.Errorf("expected 3, got %d", s.N)
+	}
+
+	for _, tt := range []struct {
+		dest interface{}
+		src  interface{}
+	}{
+		{m, []byte("{}")},
+		{&m, []byte("{")},
+		{&m, int64(1)},
+	} {
+		if err := JSON(tt.dest).Scan(tt.src); err == nil {
+			t.Errorf("%#v into %T: expected an error", tt.src, tt.dest)
+		}
+	}
+}
+
+func TestJSONParameter(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	in := map[string]interface{}{"id": 7.0, "tags": []interface{}{"a", "b"}}
+	var out map[string]interface{}
+	err := db.QueryRow("SELECT $1::jsonb", JSON(in)).Scan(JSON(&out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("expected %#v, got %#v", in, out)
+	}
+}
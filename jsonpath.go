@@ -0,0 +1,31 @@
+package pq
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// JSONPath represents a Postgres jsonpath, such as
+// `$.items[*] ? (@.price > 10)`, for binding as a parameter. jsonpath values
+// are returned as []byte, which can be scanned into a JSONPath or a string.
+type JSONPath string
+
+// Scan implements the Scanner interface.
+func (p *JSONPath) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case JSONPath:
+		*p = v
+	case []byte:
+		*p = JSONPath(v)
+	case string:
+		*p = JSONPath(v)
+	default:
+		return fmt.Errorf("pq: cannot scan %T into JSONPath", value)
+	}
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (p JSONPath) Value() (driver.Value, error) {
+	return string(p), nil
+}
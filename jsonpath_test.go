@@ -0,0 +1,41 @@
+package pq
+
+import (
+	"github.com/lib/pq/oid"
+	"testing"
+)
+
+func TestDecodeJSONPath(t *testing.T) {
+	s := []byte(`$."a"[*]`)
+	got := mustDecode(t, &parameterStatus{}, s, oid.T_jsonpath, formatText).([]byte)
+	s[0] = 'x'
+	if string(got) != `$."a"[*]` {
+		t.Errorf("expected a copy of the value, got %q", got)
+	}
+
+	var p JSONPath
+	if err := p.Scan(got); err != nil {
+		t.Fatal(err)
+	}
+	if p != `$."a"[*]` {
+		t.Errorf("expected %q, got %q", `$."a"[*]`, p)
+	}
+}
+
+func TestJSONPathParameter(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var p JSONPath
+	var ok bool
+	err := db.QueryRow(`SELECT $1::jsonpath, '{"a": [1, 2]}'::jsonb @@ $1`, JSONPath("$.a[*] > 1")).Scan(&p, &ok)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p != "($.\"a\"[*] > 1)" {
+		t.Errorf("unexpected jsonpath %q", p)
+	}
+	if !ok {
+		t.Error("expected the path to match")
+	}
+}
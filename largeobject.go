@@ -0,0 +1,105 @@
+package pq
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/lib/pq/oid"
+	"io"
+	"math"
+)
+
+// Modes for OpenLargeObject, which may be combined.
+const (
+	LargeObjectRead  = 0x40000
+	LargeObjectWrite = 0x20000
+)
+
+// LargeObject is an open large object, read and written with the server's
+// lo_* functions. Large object descriptors only live as long as the
+// transaction they were opened in, so a LargeObject must be used and closed
+// within its transaction.
+type LargeObject struct {
+	tx *sql.Tx
+	fd int32
+}
+
+// CreateLargeObject creates an empty large object and returns its OID.
+func CreateLargeObject(tx *sql.Tx) (oid.Oid, error) {
+	var o oid.Oid
+	err := tx.QueryRow("SELECT lo_create(0)").Scan(&o)
+	return o, err
+}
+
+// OpenLargeObject opens the large object o in the given mode, a combination
+// of LargeObjectRead and LargeObjectWrite.
+func OpenLargeObject(tx *sql.Tx, o oid.Oid, mode int) (*LargeObject, error) {
+	lo := &LargeObject{tx: tx}
+	err := tx.QueryRow("SELECT lo_open($1, $2)", o, mode).Scan(&lo.fd)
+	if err != nil {
+		return nil, err
+	}
+	return lo, nil
+}
+
+// UnlinkLargeObject deletes the large object o.
+func UnlinkLargeObject(tx *sql.Tx, o oid.Oid) error {
+	_, err := tx.Exec("SELECT lo_unlink($1)", o)
+	return err
+}
+
+// Read implements the io.Reader interface.
+func (lo *LargeObject) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	n := len(p)
+	if n > math.MaxInt32 {
+		n = math.MaxInt32
+	}
+
+	var b []byte
+	if err := lo.tx.QueryRow("SELECT loread($1, $2)", lo.fd, n).Scan(&b); err != nil {
+		return 0, err
+	}
+	if len(b) == 0 {
+		return 0, io.EOF
+	}
+	return copy(p, b), nil
+}
+
+// Write implements the io.Writer interface.
+func (lo *LargeObject) Write(p []byte) (int, error) {
+	var n int
+	if err := lo.tx.QueryRow("SELECT lowrite($1, $2)", lo.fd, p).Scan(&n); err != nil {
+		return 0, err
+	}
+	if n != len(p) {
+		return n, io.ErrShortWrite
+	}
+	return n, nil
+}
+
+// Seek implements the io.Seeker interface. It needs Postgres 9.3 or
+// later.
+func (lo *LargeObject) Seek(offset int64, whence int) (int64, error) {
+	// lo_lseek64 takes the same whence values as io.Seeker
+	if whence < 0 || whence > 2 {
+		return 0, errors.New("pq: invalid whence")
+	}
+	var pos int64
+	err := lo.tx.QueryRow("SELECT lo_lseek64($1, $2, $3)", lo.fd, offset, whence).Scan(&pos)
+	return pos, err
+}
+
+// Truncate truncates the large object to size bytes. It needs Postgres 9.3
+// or later.
+func (lo *LargeObject) Truncate(size int64) error {
+	_, err := lo.tx.Exec("SELECT lo_truncate64($1, $2)", lo.fd, size)
+	return err
+}
+
+// Close implements the io.Closer interface.
+func (lo *LargeObject) Close() error {
+	_, err := lo.tx.Exec("SELECT lo_close($1)", lo.fd)
+	return err
+}
@@ -0,0 +1,62 @@
+package pq
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestLargeObject(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	o, err := CreateLargeObject(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lo, err := OpenLargeObject(tx, o, LargeObjectRead|LargeObjectWrite)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := bytes.Repeat([]byte("pq\x00\xff"), 1<<14)
+	if n, err := lo.Write(data); err != nil || n != len(data) {
+		t.Fatalf("expected to write %d bytes, wrote %d: %v", len(data), n, err)
+	}
+
+	if pos, err := lo.Seek(2, 0); err != nil || pos != 2 {
+		t.Fatalf("expected position 2, got %d: %v", pos, err)
+	}
+	got, err := ioutil.ReadAll(lo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data[2:]) {
+		t.Errorf("read back %d bytes that differ from the %d written", len(got), len(data)-2)
+	}
+
+	if err := lo.Truncate(3); err != nil {
+		t.Fatal(err)
+	}
+	if pos, err := lo.Seek(0, 2); err != nil || pos != 3 {
+		t.Errorf("expected the end at 3, got %d: %v", pos, err)
+	}
+	var buf [4]byte
+	if n, err := lo.Read(buf[:]); n != 0 || err != io.EOF {
+		t.Errorf("expected EOF, got %d, %v", n, err)
+	}
+
+	if err := lo.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := UnlinkLargeObject(tx, o); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,76 @@
+package pq
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LSN represents a Postgres pg_lsn, a position in the write-ahead log. It is
+// written as two hexadecimal halves, such as "16/B374D848", but is a single
+// byte offset, so LSNs can be compared with the usual operators. pg_lsn
+// columns are decoded as text, so that they can be scanned into a string;
+// scan them into an LSN to parse them.
+type LSN uint64
+
+// String returns the text form of the LSN.
+func (l LSN) String() string {
+	return fmt.Sprintf("%X/%X", uint32(l>>32), uint32(l))
+}
+
+// Add returns the LSN n bytes after l, or before it if n is negative.
+func (l LSN) Add(n int64) LSN {
+	return LSN(int64(l) + n)
+}
+
+// Sub returns the number of bytes from m to l, as Postgres' pg_lsn
+// subtraction does; if l is before m, the result is negative.
+func (l LSN) Sub(m LSN) int64 {
+	return int64(l - m)
+}
+
+// Scan implements the Scanner interface.
+func (l *LSN) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case LSN:
+		*l = v
+		return nil
+	case []byte:
+		return l.scanText(string(v))
+	case string:
+		return l.scanText(v)
+	}
+	return fmt.Errorf("pq: cannot scan %T into LSN", value)
+}
+
+func (l *LSN) scanText(s string) error {
+	v, err := parseLSN(s)
+	if err != nil {
+		return err
+	}
+	*l = v
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (l LSN) Value() (driver.Value, error) {
+	return l.String(), nil
+}
+
+// parseLSN parses the text of a pg_lsn, such as "16/B374D848".
+func parseLSN(s string) (LSN, error) {
+	slash := strings.IndexByte(s, '/')
+	if slash < 1 || slash == len(s)-1 {
+		return 0, fmt.Errorf("pq: unable to parse pg_lsn %q", s)
+	}
+	hi, err := strconv.ParseUint(s[:slash], 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("pq: unable to parse pg_lsn %q", s)
+	}
+	lo, err := strconv.ParseUint(s[slash+1:], 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("pq: unable to parse pg_lsn %q", s)
+	}
+	return LSN(hi<<32 | lo), nil
+}
@@ -0,0 +1,83 @@
+package pq
+
+import (
+	"github.com/lib/pq/oid"
+	"testing"
+)
+
+func TestParseLSN(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  LSN
+	}{
+		{"0/0", 0},
+		{"16/B374D848", 0x16B374D848},
+		{"16/b374d848", 0x16B374D848},
+		{"FFFFFFFF/FFFFFFFF", 1<<64 - 1},
+	} {
+		var got LSN
+		if err := got.Scan([]byte(tt.input)); err != nil {
+			t.Errorf("%q: %s", tt.input, err)
+		} else if got != tt.want {
+			t.Errorf("%q: expected %v, got %#v", tt.input, tt.want, got)
+		}
+	}
+
+	got := mustDecode(t, &parameterStatus{}, []byte("16/B374D848"), oid.T_pg_lsn, formatText)
+	if b, ok := got.([]byte); !ok || string(b) != "16/B374D848" {
+		t.Errorf("expected the text of the pg_lsn, got %#v", got)
+	}
+
+	for _, input := range []string{"", "/", "16/", "/B374D848", "16B374D848", "1/2/3", "100000000/0", "G/0", "-1/0"} {
+		if _, err := parseLSN(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestLSNArithmetic(t *testing.T) {
+	l := LSN(0xFFFFFFF0)
+	if got := l.Add(0x20); got.String() != "1/10" {
+		t.Errorf("expected 1/10, got %v", got)
+	}
+	if got := l.Add(-0x10); got.String() != "0/FFFFFFE0" {
+		t.Errorf("expected 0/FFFFFFE0, got %v", got)
+	}
+	if got := l.Sub(l.Add(100)); got != -100 {
+		t.Errorf("expected -100, got %d", got)
+	}
+	if !(l < l.Add(1)) {
+		t.Error("expected l to sort before l+1")
+	}
+}
+
+func TestLSNScan(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var l, back LSN
+	err := db.QueryRow("SELECT '16/B374D848'::pg_lsn").Scan(&l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l != 0x16B374D848 {
+		t.Errorf("expected 16/B374D848, got %v", l)
+	}
+
+	err = db.QueryRow("SELECT $1::pg_lsn", l.Add(8)).Scan(&back)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back != 0x16B374D850 {
+		t.Errorf("expected 16/B374D850, got %v", back)
+	}
+
+	var s string
+	err = db.QueryRow("SELECT '16/B374D848'::pg_lsn").Scan(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "16/B374D848" {
+		t.Errorf("expected the text of the pg_lsn, got %q", s)
+	}
+}
@@ -0,0 +1,171 @@
+package pq
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Inet is an inet or cidr value. Such columns are decoded as text, so that
+// they can be scanned into a string; scan them into an Inet to parse them.
+// Mask is nil for an address without a netmask, which only an inet value
+// can be.
+type Inet struct {
+	IP   net.IP
+	Mask net.IPMask
+}
+
+// IPNet returns the address and netmask as a *net.IPNet, or nil if there is
+// no netmask.
+func (in Inet) IPNet() *net.IPNet {
+	if in.Mask == nil {
+		return nil
+	}
+	return &net.IPNet{IP: in.IP, Mask: in.Mask}
+}
+
+// Scan implements the Scanner interface.
+func (in *Inet) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("pq: cannot scan %T into Inet", value)
+	}
+
+	v, err := decodeInet(s)
+	if err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case net.IP:
+		*in = Inet{IP: v}
+	case *net.IPNet:
+		*in = Inet{IP: v.IP, Mask: v.Mask}
+	}
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (in Inet) Value() (driver.Value, error) {
+	var b []byte
+	var err error
+	if in.Mask == nil {
+		b, err = encodeIP(in.IP)
+	} else {
+		b, err = encodeIPNet(in.IPNet())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// decodeInet decodes an inet or cidr value. Addresses without a netmask,
+// which only inet values have, are returned as a net.IP; others as a
+// *net.IPNet whose IP keeps any host bits.
+func decodeInet(s string) (interface{}, error) {
+	if strings.IndexByte(s, '/') < 0 {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, fmt.Errorf("pq: unable to parse network address %q", s)
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			ip = ip4
+		}
+		return ip, nil
+	}
+
+	ip, ipnet, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil, fmt.Errorf("pq: unable to parse network address %q", s)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	return &net.IPNet{IP: ip, Mask: ipnet.Mask}, nil
+}
+
+// encodeIP renders ip as inet text.
+func encodeIP(ip net.IP) ([]byte, error) {
+	if len(ip) != net.IPv4len && len(ip) != net.IPv6len {
+		return nil, fmt.Errorf("pq: encode: invalid IP address %v", []byte(ip))
+	}
+	return []byte(ip.String()), nil
+}
+
+// encodeIPNet renders n as inet or cidr text.
+func encodeIPNet(n *net.IPNet) ([]byte, error) {
+	ones, bits := n.Mask.Size()
+	if bits == 0 {
+		return nil, fmt.Errorf("pq: encode: invalid netmask %v", []byte(n.Mask))
+	}
+	ip, err := encodeIP(n.IP)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf("%s/%d", ip, ones)), nil
+}
+
+// MACAddr is a macaddr value. Such columns are decoded as text, so that they
+// can be scanned into a string; scan them into a MACAddr to parse them.
+type MACAddr net.HardwareAddr
+
+// String returns the address in the colon-separated form.
+func (m MACAddr) String() string {
+	return net.HardwareAddr(m).String()
+}
+
+// Scan implements the Scanner interface.
+func (m *MACAddr) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("pq: cannot scan %T into MACAddr", value)
+	}
+
+	mac, err := decodeMacaddr(s)
+	if err != nil {
+		return err
+	}
+	*m = MACAddr(mac)
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (m MACAddr) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// decodeMacaddr decodes a macaddr value. Postgres always sends the
+// colon-separated form, but the other forms it accepts on input, such as
+// "08-00-2b-01-02-03", "0800.2b01.0203" or "08002b:010203", are understood
+// too.
+func decodeMacaddr(s string) (net.HardwareAddr, error) {
+	digits := make([]byte, 0, 12)
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case ':', '-', '.':
+		default:
+			digits = append(digits, c)
+		}
+	}
+
+	mac := make(net.HardwareAddr, 6)
+	if len(digits) != 12 {
+		return nil, fmt.Errorf("pq: unable to parse MAC address %q", s)
+	}
+	if _, err := hex.Decode(mac, digits); err != nil {
+		return nil, fmt.Errorf("pq: unable to parse MAC address %q", s)
+	}
+	return mac, nil
+}
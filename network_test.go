@@ -0,0 +1,162 @@
+package pq
+
+import (
+	"github.com/lib/pq/oid"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInetScan(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  Inet
+	}{
+		{"192.168.1.5", Inet{IP: net.IP{192, 168, 1, 5}}},
+		{"::1", Inet{IP: net.ParseIP("::1")}},
+		{"192.168.1.5/24", Inet{net.IP{192, 168, 1, 5}, net.CIDRMask(24, 32)}},
+		{"192.168.0.0/16", Inet{net.IP{192, 168, 0, 0}, net.CIDRMask(16, 32)}},
+		{"2001:db8::/32", Inet{net.ParseIP("2001:db8::"), net.CIDRMask(32, 128)}},
+		{"::ffff:1.2.3.4/128", Inet{net.IP{1, 2, 3, 4}, net.CIDRMask(128, 128)}},
+	} {
+		var got Inet
+		if err := got.Scan([]byte(tt.input)); err != nil {
+			t.Errorf("%q: %s", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: expected %#v, got %#v", tt.input, tt.want, got)
+		}
+
+		v, err := got.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := strings.TrimPrefix(tt.input, "::ffff:"); v != want {
+			t.Errorf("%q: expected the value %q, got %q", tt.input, want, v)
+		}
+	}
+
+	for _, input := range []interface{}{"", "1.2.3", "1.2.3.4/33", "x/8", int64(1), nil} {
+		var in Inet
+		if err := in.Scan(input); err == nil {
+			t.Errorf("%#v: expected an error", input)
+		}
+	}
+}
+
+func TestDecodeInetText(t *testing.T) {
+	for _, typ := range []oid.Oid{oid.T_inet, oid.T_cidr, oid.T_macaddr} {
+		got := mustDecode(t, &parameterStatus{}, []byte("text"), typ, formatText)
+		if b, ok := got.([]byte); !ok || string(b) != "text" {
+			t.Errorf("%d: expected the text of the value, got %#v", typ, got)
+		}
+	}
+}
+
+func TestEncodeInet(t *testing.T) {
+	for _, tt := range []struct {
+		input interface{}
+		want  string
+	}{
+		{net.IP{10, 0, 0, 1}, "10.0.0.1"},
+		{net.ParseIP("10.0.0.1"), "10.0.0.1"},
+		{net.ParseIP("2001:db8::1"), "2001:db8::1"},
+		{&net.IPNet{IP: net.IP{10, 1, 2, 3}, Mask: net.CIDRMask(8, 32)}, "10.1.2.3/8"},
+		{&net.IPNet{IP: net.ParseIP("2001:db8::"), Mask: net.CIDRMask(32, 128)}, "2001:db8::/32"},
+	} {
+		if got := string(mustEncode(t, tt.input, oid.T_inet)); got != tt.want {
+			t.Errorf("%v: expected %q, got %q", tt.input, tt.want, got)
+		}
+	}
+}
+
+func TestInetRoundTrip(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var in Inet
+	err := db.QueryRow("SELECT $1::inet", net.ParseIP("2001:db8::1")).Scan(&in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !in.IP.Equal(net.ParseIP("2001:db8::1")) || in.Mask != nil {
+		t.Errorf("unexpected address %#v", in)
+	}
+
+	err = db.QueryRow("SELECT '10.0.0.0/8'::cidr").Scan(&in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := in.IPNet(); n == nil || n.String() != "10.0.0.0/8" {
+		t.Errorf("unexpected network %#v", in)
+	}
+
+	var s string
+	err = db.QueryRow("SELECT $1::cidr", in).Scan(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "10.0.0.0/8" {
+		t.Errorf("expected the network's text, got %q", s)
+	}
+}
+
+func TestMACAddrScan(t *testing.T) {
+	want := MACAddr{0x08, 0x00, 0x2b, 0x01, 0x02, 0x03}
+	for _, input := range []string{
+		"08:00:2b:01:02:03",
+		"08-00-2b-01-02-03",
+		"08002b:010203",
+		"08002b-010203",
+		"0800.2b01.0203",
+		"0800-2b01-0203",
+		"08002b010203",
+		"08:00:2B:01:02:03",
+	} {
+		var got MACAddr
+		if err := got.Scan([]byte(input)); err != nil {
+			t.Errorf("%q: %s", input, err)
+		} else if !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: expected %v, got %v", input, want, got)
+		}
+	}
+
+	for _, input := range []interface{}{"", "08:00:2b:01:02", "08:00:2b:01:02:03:04", "zz:00:2b:01:02:03", int64(1), nil} {
+		var m MACAddr
+		if err := m.Scan(input); err == nil {
+			t.Errorf("%#v: expected an error", input)
+		}
+	}
+
+	if v, err := want.Value(); err != nil || v != "08:00:2b:01:02:03" {
+		t.Errorf("unexpected value %#v, %v", v, err)
+	}
+}
+
+func TestMACAddrRoundTrip(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	mac := MACAddr{0x08, 0x00, 0x2b, 0x01, 0x02, 0xff}
+	var got MACAddr
+	var s string
+	err := db.QueryRow("SELECT $1::macaddr, $1::macaddr", mac).Scan(&got, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, mac) {
+		t.Errorf("expected %v, got %v", mac, got)
+	}
+	if s != "08:00:2b:01:02:ff" {
+		t.Errorf("expected the address's text, got %q", s)
+	}
+}
+
+func TestEncodeMacaddr(t *testing.T) {
+	mac := net.HardwareAddr{0x08, 0x00, 0x2b, 0x01, 0x02, 0xff}
+	if got := string(mustEncode(t, mac, oid.T_macaddr)); got != "08:00:2b:01:02:ff" {
+		t.Errorf("unexpected encoding %q", got)
+	}
+}
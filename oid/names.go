@@ -0,0 +1,173 @@
+package oid
+
+// TypeName maps the OIDs of the built-in types to their names, such as
+// "int4", or "_int4" for its array type. It is generated along with the
+// constants in types.go.
+var TypeName = map[Oid]string{
+	T_bool:             "bool",
+	T_bytea:            "bytea",
+	T_char:             "char",
+	T_name:             "name",
+	T_int8:             "int8",
+	T_int2:             "int2",
+	T_int2vector:       "int2vector",
+	T_int4:             "int4",
+	T_regproc:          "regproc",
+	T_text:             "text",
+	T_oid:              "oid",
+	T_tid:              "tid",
+	T_xid:              "xid",
+	T_cid:              "cid",
+	T_oidvector:        "oidvector",
+	T_pg_type:          "pg_type",
+	T_pg_attribute:     "pg_attribute",
+	T_pg_proc:          "pg_proc",
+	T_pg_class:         "pg_class",
+	T_json:             "json",
+	T_xml:              "xml",
+	T__xml:             "_xml",
+	T_pg_node_tree:     "pg_node_tree",
+	T__json:            "_json",
+	T_smgr:             "smgr",
+	T_point:            "point",
+	T_lseg:             "lseg",
+	T_path:             "path",
+	T_box:              "box",
+	T_polygon:          "polygon",
+	T_line:             "line",
+	T__line:            "_line",
+	T_cidr:             "cidr",
+	T__cidr:            "_cidr",
+	T_float4:           "float4",
+	T_float8:           "float8",
+	T_abstime:          "abstime",
+	T_reltime:          "reltime",
+	T_tinterval:        "tinterval",
+	T_unknown:          "unknown",
+	T_circle:           "circle",
+	T__circle:          "_circle",
+	T_money:            "money",
+	T__money:           "_money",
+	T_macaddr:          "macaddr",
+	T_inet:             "inet",
+	T__bool:            "_bool",
+	T__bytea:           "_bytea",
+	T__char:            "_char",
+	T__name:            "_name",
+	T__int2:            "_int2",
+	T__int2vector:      "_int2vector",
+	T__int4:            "_int4",
+	T__regproc:         "_regproc",
+	T__text:            "_text",
+	T__tid:             "_tid",
+	T__xid:             "_xid",
+	T__cid:             "_cid",
+	T__oidvector:       "_oidvector",
+	T__bpchar:          "_bpchar",
+	T__varchar:         "_varchar",
+	T__int8:            "_int8",
+	T__point:           "_point",
+	T__lseg:            "_lseg",
+	T__path:            "_path",
+	T__box:             "_box",
+	T__float4:          "_float4",
+	T__float8:          "_float8",
+	T__abstime:         "_abstime",
+	T__reltime:         "_reltime",
+	T__tinterval:       "_tinterval",
+	T__polygon:         "_polygon",
+	T__oid:             "_oid",
+	T_aclitem:          "aclitem",
+	T__aclitem:         "_aclitem",
+	T__macaddr:         "_macaddr",
+	T__inet:            "_inet",
+	T_bpchar:           "bpchar",
+	T_varchar:          "varchar",
+	T_date:             "date",
+	T_time:             "time",
+	T_timestamp:        "timestamp",
+	T__timestamp:       "_timestamp",
+	T__date:            "_date",
+	T__time:            "_time",
+	T_timestamptz:      "timestamptz",
+	T__timestamptz:     "_timestamptz",
+	T_interval:         "interval",
+	T__interval:        "_interval",
+	T__numeric:         "_numeric",
+	T_pg_database:      "pg_database",
+	T__cstring:         "_cstring",
+	T_timetz:           "timetz",
+	T__timetz:          "_timetz",
+	T_bit:              "bit",
+	T__bit:             "_bit",
+	T_varbit:           "varbit",
+	T__varbit:          "_varbit",
+	T_numeric:          "numeric",
+	T_refcursor:        "refcursor",
+	T__refcursor:       "_refcursor",
+	T_regprocedure:     "regprocedure",
+	T_regoper:          "regoper",
+	T_regoperator:      "regoperator",
+	T_regclass:         "regclass",
+	T_regtype:          "regtype",
+	T__regprocedure:    "_regprocedure",
+	T__regoper:         "_regoper",
+	T__regoperator:     "_regoperator",
+	T__regclass:        "_regclass",
+	T__regtype:         "_regtype",
+	T_record:           "record",
+	T_cstring:          "cstring",
+	T_any:              "any",
+	T_anyarray:         "anyarray",
+	T_void:             "void",
+	T_trigger:          "trigger",
+	T_language_handler: "language_handler",
+	T_internal:         "internal",
+	T_opaque:           "opaque",
+	T_anyelement:       "anyelement",
+	T__record:          "_record",
+	T_anynonarray:      "anynonarray",
+	T_pg_authid:        "pg_authid",
+	T_pg_auth_members:  "pg_auth_members",
+	T__txid_snapshot:   "_txid_snapshot",
+	T_uuid:             "uuid",
+	T__uuid:            "_uuid",
+	T_txid_snapshot:    "txid_snapshot",
+	T_fdw_handler:      "fdw_handler",
+	T_pg_lsn:           "pg_lsn",
+	T__pg_lsn:          "_pg_lsn",
+	T_anyenum:          "anyenum",
+	T_tsvector:         "tsvector",
+	T_tsquery:          "tsquery",
+	T_gtsvector:        "gtsvector",
+	T__tsvector:        "_tsvector",
+	T__gtsvector:       "_gtsvector",
+	T__tsquery:         "_tsquery",
+	T_regconfig:        "regconfig",
+	T__regconfig:       "_regconfig",
+	T_regdictionary:    "regdictionary",
+	T__regdictionary:   "_regdictionary",
+	T_jsonb:            "jsonb",
+	T__jsonb:           "_jsonb",
+	T_anyrange:         "anyrange",
+	T_int4range:        "int4range",
+	T__int4range:       "_int4range",
+	T_numrange:         "numrange",
+	T__numrange:        "_numrange",
+	T_tsrange:          "tsrange",
+	T__tsrange:         "_tsrange",
+	T_tstzrange:        "tstzrange",
+	T__tstzrange:       "_tstzrange",
+	T_daterange:        "daterange",
+	T__daterange:       "_daterange",
+	T_int8range:        "int8range",
+	T__int8range:       "_int8range",
+	T_jsonpath:         "jsonpath",
+	T__jsonpath:        "_jsonpath",
+	T_regnamespace:     "regnamespace",
+	T__regnamespace:    "_regnamespace",
+	T_regrole:          "regrole",
+	T__regrole:         "_regrole",
+	T_pg_snapshot:      "pg_snapshot",
+	T__pg_snapshot:     "_pg_snapshot",
+}
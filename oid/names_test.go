@@ -0,0 +1,17 @@
+package oid
+
+import "testing"
+
+func TestTypeName(t *testing.T) {
+	for typ, want := range map[Oid]string{
+		T_int4:    "int4",
+		T__int4:   "_int4",
+		T_bpchar:  "bpchar",
+		T_pg_lsn:  "pg_lsn",
+		T__pg_lsn: "_pg_lsn",
+	} {
+		if got := TypeName[typ]; got != want {
+			t.Errorf("%d: expected %q, got %q", typ, want, got)
+		}
+	}
+}
@@ -142,6 +142,8 @@ const (
 	T__uuid                = 2951
 	T_txid_snapshot        = 2970
 	T_fdw_handler          = 3115
+	T_pg_lsn               = 3220
+	T__pg_lsn              = 3221
 	T_anyenum              = 3500
 	T_tsvector             = 3614
 	T_tsquery              = 3615
@@ -153,6 +155,8 @@ const (
 	T__regconfig           = 3735
 	T_regdictionary        = 3769
 	T__regdictionary       = 3770
+	T_jsonb                = 3802
+	T__jsonb               = 3807
 	T_anyrange             = 3831
 	T_int4range            = 3904
 	T__int4range           = 3905
@@ -166,4 +170,12 @@ const (
 	T__daterange           = 3913
 	T_int8range            = 3926
 	T__int8range           = 3927
+	T_jsonpath             = 4072
+	T__jsonpath            = 4073
+	T_regnamespace         = 4089
+	T__regnamespace        = 4090
+	T_regrole              = 4096
+	T__regrole             = 4097
+	T_pg_snapshot          = 5038
+	T__pg_snapshot         = 5039
 )
@@ -0,0 +1,191 @@
+package pq
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"github.com/lib/pq/oid"
+)
+
+// Range represents a value of one of Postgres' range types. Range columns
+// are decoded as text, so that they can be scanned into a string; scanned
+// into a Range, they have the text of their bounds as strings. When binding
+// a Range, the bounds may be of any type that can be a query parameter.
+type Range struct {
+	// The bounds of the range, or nil if the range is unbounded on that
+	// side.
+	Lower, Upper interface{}
+
+	// Whether the range includes its bounds.
+	LowerInc, UpperInc bool
+
+	// Whether the range is empty, in which case the other fields are
+	// unset.
+	Empty bool
+}
+
+// Scan implements the Scanner interface.
+func (r *Range) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case Range:
+		*r = v
+		return nil
+	case []byte:
+		return r.scanText(string(v))
+	case string:
+		return r.scanText(v)
+	}
+	return fmt.Errorf("pq: cannot scan %T into Range", value)
+}
+
+func (r *Range) scanText(s string) error {
+	v, err := parseRange(s)
+	if err != nil {
+		return err
+	}
+	*r = Range{
+		LowerInc: v.lowerInc,
+		UpperInc: v.upperInc,
+		Empty:    v.empty,
+	}
+	if v.lower != nil {
+		r.Lower = string(v.lower)
+	}
+	if v.upper != nil {
+		r.Upper = string(v.upper)
+	}
+	return nil
+}
+
+// Value implements the driver Valuer interface. The bounds are encoded
+// like query parameters.
+func (r Range) Value() (driver.Value, error) {
+	if r.Empty {
+		return "empty", nil
+	}
+
+	var b []byte
+	if r.LowerInc {
+		b = append(b, '[')
+	} else {
+		b = append(b, '(')
+	}
+	b, err := appendRangeBound(b, r.Lower)
+	if err != nil {
+		return nil, err
+	}
+	b = append(b, ',')
+	if b, err = appendRangeBound(b, r.Upper); err != nil {
+		return nil, err
+	}
+	if r.UpperInc {
+		b = append(b, ']')
+	} else {
+		b = append(b, ')')
+	}
+	return string(b), nil
+}
+
+// appendRangeBound appends the bound v to b, quoting and escaping it. An
+// unbounded side is left blank.
+func appendRangeBound(b []byte, v interface{}) ([]byte, error) {
+	e, err := encode(&parameterStatus{}, v, oid.T_unknown)
+	if err != nil || e == nil {
+		return b, err
+	}
+
+	b = append(b, '"')
+	for _, c := range e {
+		if c == '"' || c == '\\' {
+			b = append(b, '\\')
+		}
+		b = append(b, c)
+	}
+	return append(b, '"'), nil
+}
+
+// rangeText is the text of a range's bounds, as split up by parseRange. A
+// nil bound is unbounded.
+type rangeText struct {
+	lower, upper       []byte
+	lowerInc, upperInc bool
+	empty              bool
+}
+
+// parseRange splits the text of a range, such as `[1,10)`, `(,"b c"]` or
+// `empty`, into its bounds.
+func parseRange(s string) (rangeText, error) {
+	var r rangeText
+	fail := func() (rangeText, error) {
+		return rangeText{}, fmt.Errorf("pq: unable to parse range %q", s)
+	}
+
+	if s == "empty" {
+		r.empty = true
+		return r, nil
+	}
+	if len(s) < 3 {
+		return fail()
+	}
+
+	switch s[0] {
+	case '[':
+		r.lowerInc = true
+	case '(':
+	default:
+		return fail()
+	}
+	switch s[len(s)-1] {
+	case ']':
+		r.upperInc = true
+	case ')':
+	default:
+		return fail()
+	}
+
+	rest := s[1 : len(s)-1]
+	var ok bool
+	if r.lower, rest, ok = parseRangeBound(rest); !ok || len(rest) == 0 || rest[0] != ',' {
+		return fail()
+	}
+	if r.upper, rest, ok = parseRangeBound(rest[1:]); !ok || len(rest) != 0 {
+		return fail()
+	}
+	return r, nil
+}
+
+// parseRangeBound reads a bound from the start of s, up to a comma or the
+// end of s, returning it and what is left of s. Within a bound, a backslash
+// escapes the next character, and double quotes protect commas and spaces;
+// a doubled double quote inside them stands for one. An empty bound is nil.
+func parseRangeBound(s string) (bound []byte, rest string, ok bool) {
+	if len(s) == 0 || s[0] == ',' {
+		return nil, s, true
+	}
+
+	bound = []byte{}
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\':
+			i++
+			if i == len(s) {
+				return nil, "", false
+			}
+			bound = append(bound, s[i])
+		case c == '"' && quoted && i+1 < len(s) && s[i+1] == '"':
+			bound = append(bound, '"')
+			i++
+		case c == '"':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			return bound, s[i:], true
+		default:
+			bound = append(bound, c)
+		}
+	}
+	if quoted {
+		return nil, "", false
+	}
+	return bound, "", true
+}
@@ -0,0 +1,108 @@
+package pq
+
+import (
+	"github.com/lib/pq/oid"
+	"testing"
+	"time"
+)
+
+func TestDecodeRange(t *testing.T) {
+	for _, typ := range []oid.Oid{oid.T_int4range, oid.T_tstzrange} {
+		got := mustDecode(t, &parameterStatus{}, []byte("[1,10)"), typ, formatText)
+		if b, ok := got.([]byte); !ok || string(b) != "[1,10)" {
+			t.Errorf("%d: expected the text of the range, got %#v", typ, got)
+		}
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	for _, tt := range []struct {
+		input        string
+		lower, upper string
+	}{
+		{`["a b","c,d"]`, "a b", "c,d"},
+		{`["a""b",c\"d)`, `a"b`, `c"d`},
+		{`("",x)`, "", "x"},
+		{`[a\,b,c]`, "a,b", "c"},
+	} {
+		r, err := parseRange(tt.input)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if string(r.lower) != tt.lower || string(r.upper) != tt.upper {
+			t.Errorf("%q: expected %q and %q, got %q and %q",
+				tt.input, tt.lower, tt.upper, r.lower, r.upper)
+		}
+		if r.lower == nil {
+			t.Errorf("%q: expected a lower bound", tt.input)
+		}
+	}
+
+	for _, input := range []string{``, `[]`, `[1,2`, `1,2]`, `[1,2,3]`, `["1,2]`, `[1\`, `[1)`, `emptyish`} {
+		if _, err := parseRange(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestRangeScanValue(t *testing.T) {
+	var r Range
+	if err := r.Scan([]byte(`[1,"a b")`)); err != nil {
+		t.Fatal(err)
+	}
+	if want := (Range{Lower: "1", Upper: "a b", LowerInc: true}); r != want {
+		t.Errorf("expected %#v, got %#v", want, r)
+	}
+	if err := r.Scan("empty"); err != nil || r != (Range{Empty: true}) {
+		t.Errorf("expected an empty range, got %#v, %v", r, err)
+	}
+	if err := r.Scan(`(,"2020-01-01 00:00:00"]`); err != nil {
+		t.Fatal(err)
+	}
+	if want := (Range{Upper: "2020-01-01 00:00:00", UpperInc: true}); r != want {
+		t.Errorf("expected %#v, got %#v", want, r)
+	}
+	if err := r.Scan(nil); err == nil {
+		t.Error("expected an error scanning nil into a Range")
+	}
+
+	for _, tt := range []struct {
+		r    Range
+		want string
+	}{
+		{Range{Empty: true}, "empty"},
+		{Range{}, "(,)"},
+		{Range{Lower: int64(1), Upper: int64(10), LowerInc: true}, `["1","10")`},
+		{Range{Upper: `a"b\c`, UpperInc: true}, `(,"a\"b\\c"]`},
+		{Range{Lower: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), LowerInc: true}, `["2020-01-01 00:00:00Z",)`},
+	} {
+		got, err := tt.r.Value()
+		if err != nil {
+			t.Errorf("%#v: unexpected error: %v", tt.r, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%#v: expected %s, got %v", tt.r, tt.want, got)
+		}
+	}
+}
+
+func TestRangeRoundTrip(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var r Range
+	var s string
+	err := db.QueryRow("SELECT $1::int4range, $1::int4range", Range{Lower: int64(1), Upper: int64(10), UpperInc: true}).Scan(&r, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Postgres normalizes discrete ranges to include their lower bound
+	if want := (Range{Lower: "2", Upper: "11", LowerInc: true}); r != want {
+		t.Errorf("expected %#v, got %#v", want, r)
+	}
+	if s != "[2,11)" {
+		t.Errorf("expected the text of the range, got %q", s)
+	}
+}
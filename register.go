@@ -0,0 +1,57 @@
+package pq
+
+import (
+	"github.com/lib/pq/oid"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+var (
+	registryMu sync.RWMutex
+	encoders   = map[reflect.Type]func(x interface{}) ([]byte, error){}
+	decoders   = map[oid.Oid]func(s []byte) (interface{}, error){}
+)
+
+// RegisterEncoder teaches the driver to send parameters of the same type as
+// example, which it would otherwise reject or convert, as the text returned
+// by enc, or NULL if that is nil. Valuers are still sent as their values.
+// RegisterEncoder is meant to be called from init functions; it panics if
+// an encoder is already registered for the type.
+func RegisterEncoder(example interface{}, enc func(x interface{}) ([]byte, error)) {
+	typ := reflect.TypeOf(example)
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, dup := encoders[typ]; dup {
+		panic("pq: RegisterEncoder called twice for type " + typ.String())
+	}
+	encoders[typ] = enc
+}
+
+// RegisterDecoder teaches the driver to return values of the type typ, such
+// as an extension type whose OID is known, as the result of dec rather than
+// as a []byte. dec is passed the value in the text format, which is only
+// valid until the next row is read. It cannot replace the decoding of the
+// types the driver already understands. RegisterDecoder is meant to be
+// called from init functions; it panics if a decoder is already registered
+// for typ.
+func RegisterDecoder(typ oid.Oid, dec func(s []byte) (interface{}, error)) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, dup := decoders[typ]; dup {
+		panic("pq: RegisterDecoder called twice for type " + strconv.Itoa(int(typ)))
+	}
+	decoders[typ] = dec
+}
+
+func registeredEncoder(x interface{}) func(x interface{}) ([]byte, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return encoders[reflect.TypeOf(x)]
+}
+
+func registeredDecoder(typ oid.Oid) func(s []byte) (interface{}, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return decoders[typ]
+}
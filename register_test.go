@@ -0,0 +1,99 @@
+package pq
+
+import (
+	"errors"
+	"github.com/lib/pq/oid"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testColor int
+
+func encodeTestColor(x interface{}) ([]byte, error) {
+	switch x.(testColor) {
+	case 0:
+		return []byte("red"), nil
+	case 1:
+		return []byte("green"), nil
+	}
+	return nil, errors.New("unknown color")
+}
+
+func TestRegisterEncoder(t *testing.T) {
+	if _, err := encode(&parameterStatus{}, testColor(1), oid.T_text); err == nil {
+		t.Fatal("expected an error before the encoder is registered")
+	}
+
+	RegisterEncoder(testColor(0), encodeTestColor)
+	defer func() {
+		registryMu.Lock()
+		delete(encoders, reflect.TypeOf(testColor(0)))
+		registryMu.Unlock()
+	}()
+
+	if got := string(mustEncode(t, testColor(1), oid.T_text)); got != "green" {
+		t.Errorf("expected green, got %q", got)
+	}
+	c := testColor(0)
+	if got := string(mustEncode(t, &c, oid.T_text)); got != "red" {
+		t.Errorf("expected red, got %q", got)
+	}
+	if _, err := encode(&parameterStatus{}, testColor(2), oid.T_text); err == nil {
+		t.Error("expected the encoder's error")
+	}
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "testColor") {
+			t.Errorf("expected a panic naming the type, got %v", r)
+		}
+	}()
+	RegisterEncoder(testColor(0), encodeTestColor)
+}
+
+func TestRegisterDecoder(t *testing.T) {
+	const typ = oid.Oid(99999)
+	if got := mustDecode(t, &parameterStatus{}, []byte("abc"), typ, formatText); string(got.([]byte)) != "abc" {
+		t.Fatalf("expected the raw value before the decoder is registered, got %#v", got)
+	}
+
+	RegisterDecoder(typ, func(s []byte) (interface{}, error) {
+		if len(s) == 0 {
+			return nil, errors.New("empty")
+		}
+		return strings.ToUpper(string(s)), nil
+	})
+	defer func() {
+		registryMu.Lock()
+		delete(decoders, typ)
+		registryMu.Unlock()
+	}()
+
+	if got := mustDecode(t, &parameterStatus{}, []byte("abc"), typ, formatText); got != "ABC" {
+		t.Errorf("expected ABC, got %#v", got)
+	}
+	if _, err := decode(&parameterStatus{}, []byte{}, typ, formatText); err == nil {
+		t.Error("expected the decoder's error")
+	}
+}
+
+func TestRegisteredEncoderParameter(t *testing.T) {
+	RegisterEncoder(testColor(0), encodeTestColor)
+	defer func() {
+		registryMu.Lock()
+		delete(encoders, reflect.TypeOf(testColor(0)))
+		registryMu.Unlock()
+	}()
+
+	db := openTestConn(t)
+	defer db.Close()
+
+	var s string
+	err := db.QueryRow("SELECT $1::text", testColor(1)).Scan(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "green" {
+		t.Errorf("expected green, got %q", s)
+	}
+}
@@ -0,0 +1,122 @@
+package pq
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Snapshot represents a Postgres pg_snapshot or txid_snapshot, such as is
+// returned by pg_current_snapshot(): which transactions were in progress,
+// and so invisible, when the snapshot was taken. Its text form is
+// "xmin:xmax:xip_list", such as "10:20:10,14,15". pg_snapshot and
+// txid_snapshot columns are decoded as text, so that they can be scanned
+// into a string; scan them into a Snapshot to parse them.
+type Snapshot struct {
+	// The earliest transaction that was still active; every transaction
+	// before it is visible.
+	Xmin uint64
+
+	// The first transaction that had not yet been assigned; it and every
+	// transaction after it are invisible.
+	Xmax uint64
+
+	// The transactions between Xmin and Xmax that were still active, in
+	// ascending order.
+	Xip []uint64
+}
+
+// Visible reports whether the transaction txid is visible in the snapshot,
+// as Postgres' pg_visible_in_snapshot does: whether it had committed or
+// rolled back when the snapshot was taken.
+func (s Snapshot) Visible(txid uint64) bool {
+	if txid < s.Xmin {
+		return true
+	}
+	if txid >= s.Xmax {
+		return false
+	}
+	for _, x := range s.Xip {
+		if x == txid {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the text form of the snapshot.
+func (s Snapshot) String() string {
+	b := strconv.AppendUint(nil, s.Xmin, 10)
+	b = append(b, ':')
+	b = strconv.AppendUint(b, s.Xmax, 10)
+	b = append(b, ':')
+	for i, x := range s.Xip {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = strconv.AppendUint(b, x, 10)
+	}
+	return string(b)
+}
+
+// Scan implements the Scanner interface.
+func (s *Snapshot) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case Snapshot:
+		*s = v
+		return nil
+	case []byte:
+		return s.scanText(string(v))
+	case string:
+		return s.scanText(v)
+	}
+	return fmt.Errorf("pq: cannot scan %T into Snapshot", value)
+}
+
+func (s *Snapshot) scanText(str string) error {
+	v, err := parseSnapshot(str)
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (s Snapshot) Value() (driver.Value, error) {
+	return s.String(), nil
+}
+
+// parseSnapshot parses the text of a pg_snapshot or txid_snapshot, such as
+// "10:20:10,14,15" or "10:10:".
+func parseSnapshot(str string) (Snapshot, error) {
+	fail := func() (Snapshot, error) {
+		return Snapshot{}, fmt.Errorf("pq: unable to parse snapshot %q", str)
+	}
+
+	fields := strings.Split(str, ":")
+	if len(fields) != 3 {
+		return fail()
+	}
+	var s Snapshot
+	var err error
+	if s.Xmin, err = strconv.ParseUint(fields[0], 10, 64); err != nil {
+		return fail()
+	}
+	if s.Xmax, err = strconv.ParseUint(fields[1], 10, 64); err != nil || s.Xmax < s.Xmin {
+		return fail()
+	}
+	if fields[2] == "" {
+		return s, nil
+	}
+
+	for _, f := range strings.Split(fields[2], ",") {
+		x, err := strconv.ParseUint(f, 10, 64)
+		if err != nil || x < s.Xmin || x >= s.Xmax {
+			return fail()
+		}
+		s.Xip = append(s.Xip, x)
+	}
+	return s, nil
+}
@@ -0,0 +1,92 @@
+package pq
+
+import (
+	"github.com/lib/pq/oid"
+	"reflect"
+	"testing"
+)
+
+func TestParseSnapshot(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  Snapshot
+	}{
+		{"10:10:", Snapshot{Xmin: 10, Xmax: 10}},
+		{"10:20:10,14,15", Snapshot{Xmin: 10, Xmax: 20, Xip: []uint64{10, 14, 15}}},
+		{"18446744073709551614:18446744073709551615:", Snapshot{Xmin: 1<<64 - 2, Xmax: 1<<64 - 1}},
+	} {
+		var got Snapshot
+		if err := got.Scan([]byte(tt.input)); err != nil {
+			t.Errorf("%q: %s", tt.input, err)
+		} else if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: expected %#v, got %#v", tt.input, tt.want, got)
+		}
+		if got := tt.want.String(); got != tt.input {
+			t.Errorf("expected %q, got %q", tt.input, got)
+		}
+	}
+
+	for _, input := range []string{"", "10:20", "10:20:1:2", "a:20:", "20:10:", "10:20:9", "10:20:20", "10:20:11,", "-1:20:"} {
+		if _, err := parseSnapshot(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+
+	for _, typ := range []oid.Oid{oid.T_pg_snapshot, oid.T_txid_snapshot} {
+		got := mustDecode(t, &parameterStatus{}, []byte("10:20:10"), typ, formatText)
+		if b, ok := got.([]byte); !ok || string(b) != "10:20:10" {
+			t.Errorf("%d: expected the text of the snapshot, got %#v", typ, got)
+		}
+	}
+}
+
+func TestSnapshotVisible(t *testing.T) {
+	s := Snapshot{Xmin: 10, Xmax: 20, Xip: []uint64{10, 14, 15}}
+	for txid, want := range map[uint64]bool{
+		1:  true,
+		9:  true,
+		10: false,
+		11: true,
+		14: false,
+		15: false,
+		19: true,
+		20: false,
+		99: false,
+	} {
+		if got := s.Visible(txid); got != want {
+			t.Errorf("%d: expected %v, got %v", txid, want, got)
+		}
+	}
+}
+
+func TestSnapshotScan(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var s, back Snapshot
+	err := db.QueryRow("SELECT '10:20:10,14,15'::txid_snapshot").Scan(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Snapshot{Xmin: 10, Xmax: 20, Xip: []uint64{10, 14, 15}}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("expected %v, got %v", want, s)
+	}
+
+	err = db.QueryRow("SELECT $1::txid_snapshot", s).Scan(&back)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, want) {
+		t.Errorf("expected %v, got %v", want, back)
+	}
+
+	var str string
+	err = db.QueryRow("SELECT '10:20:10,14,15'::txid_snapshot").Scan(&str)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if str != "10:20:10,14,15" {
+		t.Errorf("expected the text of the snapshot, got %q", str)
+	}
+}
@@ -0,0 +1,73 @@
+package pq
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TID represents a Postgres tid, the physical location of a row version,
+// as found in the ctid system column. tid columns are decoded as text, so
+// that they can be scanned into a string; scan them into a TID to parse
+// them.
+type TID struct {
+	Block  uint32
+	Offset uint16
+}
+
+// String returns the text form of the TID, such as "(0,1)".
+func (t TID) String() string {
+	return "(" + strconv.FormatUint(uint64(t.Block), 10) + "," + strconv.FormatUint(uint64(t.Offset), 10) + ")"
+}
+
+// Scan implements the Scanner interface.
+func (t *TID) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case TID:
+		*t = v
+		return nil
+	case []byte:
+		return t.scanText(string(v))
+	case string:
+		return t.scanText(v)
+	}
+	return fmt.Errorf("pq: cannot scan %T into TID", value)
+}
+
+func (t *TID) scanText(s string) error {
+	v, err := parseTID(s)
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (t TID) Value() (driver.Value, error) {
+	return t.String(), nil
+}
+
+// parseTID parses the text of a tid, such as "(0,1)".
+func parseTID(s string) (TID, error) {
+	fail := func() (TID, error) {
+		return TID{}, fmt.Errorf("pq: unable to parse tid %q", s)
+	}
+	if len(s) < 2 || s[0] != '(' || s[len(s)-1] != ')' {
+		return fail()
+	}
+	comma := strings.IndexByte(s, ',')
+	if comma < 0 {
+		return fail()
+	}
+	block, err := strconv.ParseUint(s[1:comma], 10, 32)
+	if err != nil {
+		return fail()
+	}
+	offset, err := strconv.ParseUint(s[comma+1:len(s)-1], 10, 16)
+	if err != nil {
+		return fail()
+	}
+	return TID{uint32(block), uint16(offset)}, nil
+}
@@ -0,0 +1,84 @@
+package pq
+
+import (
+	"github.com/lib/pq/oid"
+	"testing"
+)
+
+func TestParseTID(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  TID
+	}{
+		{"(0,1)", TID{0, 1}},
+		{"(4294967295,65535)", TID{4294967295, 65535}},
+	} {
+		var got TID
+		if err := got.Scan([]byte(tt.input)); err != nil {
+			t.Errorf("%q: %s", tt.input, err)
+		} else if got != tt.want {
+			t.Errorf("%q: expected %v, got %#v", tt.input, tt.want, got)
+		}
+		if s := tt.want.String(); s != tt.input {
+			t.Errorf("expected %q, got %q", tt.input, s)
+		}
+	}
+
+	for _, input := range []string{"", "()", "(0)", "0,1", "(0,1", "(-1,1)", "(4294967296,0)", "(0,65536)", "(0,1,2)"} {
+		if _, err := parseTID(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+
+	got := mustDecode(t, &parameterStatus{}, []byte("(0,1)"), oid.T_tid, formatText)
+	if b, ok := got.([]byte); !ok || string(b) != "(0,1)" {
+		t.Errorf("expected the text of the tid, got %#v", got)
+	}
+}
+
+func TestTIDParameter(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("CREATE TEMP TABLE temp (a int)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = tx.Exec("INSERT INTO temp VALUES (1), (2)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tid TID
+	err = tx.QueryRow("SELECT ctid FROM temp WHERE a = 2").Scan(&tid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tid != (TID{0, 2}) {
+		t.Errorf("expected (0,2), got %v", tid)
+	}
+
+	var s string
+	err = tx.QueryRow("SELECT ctid FROM temp WHERE a = 2").Scan(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "(0,2)" {
+		t.Errorf("expected (0,2), got %q", s)
+	}
+
+	var a int
+	err = tx.QueryRow("SELECT a FROM temp WHERE ctid = $1", tid).Scan(&a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != 2 {
+		t.Errorf("expected 2, got %d", a)
+	}
+}
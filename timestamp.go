@@ -0,0 +1,310 @@
+package pq
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// tsParser reads the fields of a date or timestamp in the ISO DateStyle.
+// The first malformed field sets ok to false, and records what was expected
+// in its place, after which every read returns zero.
+type tsParser struct {
+	str string // the whole input
+	s   string // what is left of it
+	ok  bool
+
+	// What was expected where parsing failed, and its offset in str.
+	want string
+	at   int
+}
+
+func newTsParser(str string) *tsParser {
+	return &tsParser{str: str, s: str, ok: true}
+}
+
+// fail records that want was expected next, unless parsing has already
+// failed.
+func (p *tsParser) fail(want string) {
+	if p.ok {
+		p.ok = false
+		p.want = want
+		p.at = len(p.str) - len(p.s)
+	}
+}
+
+// error returns the error for input that failed to parse as a typ, saying
+// what was expected where, if that is known.
+func (p *tsParser) error(typ string) error {
+	if p.want == "" {
+		return fmt.Errorf("pq: unable to parse %s %q", typ, p.str)
+	}
+	return fmt.Errorf("pq: unable to parse %s %q: expected %s at offset %d", typ, p.str, p.want, p.at)
+}
+
+// digits reads a number of between min and max digits, inclusive.
+func (p *tsParser) digits(min, max int) int {
+	n, i := 0, 0
+	for ; p.ok && i < len(p.s) && i < max && '0' <= p.s[i] && p.s[i] <= '9'; i++ {
+		n = n*10 + int(p.s[i]-'0')
+	}
+	if i < min {
+		if min == max {
+			p.fail(fmt.Sprintf("%d digits", min))
+		} else {
+			p.fail(fmt.Sprintf("%d to %d digits", min, max))
+		}
+	}
+	if !p.ok {
+		return 0
+	}
+	p.s = p.s[i:]
+	return n
+}
+
+// field reads a number of exactly two digits, from min to max.
+func (p *tsParser) field(min, max int) int {
+	s := p.s
+	n := p.digits(2, 2)
+	if p.ok && (n < min || n > max) {
+		p.s = s
+		p.fail(fmt.Sprintf("a number from %02d to %02d", min, max))
+	}
+	return n
+}
+
+// expect reads the byte c.
+func (p *tsParser) expect(c byte) {
+	if !p.ok || len(p.s) == 0 || p.s[0] != c {
+		p.fail(fmt.Sprintf("%q", c))
+		return
+	}
+	p.s = p.s[1:]
+}
+
+// end checks that the input has all been read.
+func (p *tsParser) end() {
+	if p.ok && len(p.s) != 0 {
+		p.fail("the end of the value")
+	}
+}
+
+// peek reports whether the next byte is c.
+func (p *tsParser) peek(c byte) bool {
+	return p.ok && len(p.s) > 0 && p.s[0] == c
+}
+
+// nanoseconds reads the fractional part of a second, after the decimal
+// point, into nanoseconds. Digits past the ninth round the result to the
+// nearest nanosecond, which may be a whole second.
+func (p *tsParser) nanoseconds() int {
+	frac := len(p.s)
+	ns := p.digits(1, 9)
+	frac -= len(p.s)
+	for ; frac < 9; frac++ {
+		ns *= 10
+	}
+	if p.ok && len(p.s) > 0 && '5' <= p.s[0] && p.s[0] <= '9' {
+		ns++
+	}
+	p.digits(0, len(p.s))
+	return ns
+}
+
+// ParseTimestamp parses the text of a Postgres date, timestamp or
+// timestamptz in the ISO DateStyle, such as "2012-11-06",
+// "2012-11-06 10:23:42.123456" or "0044-03-15 12:00:00+00:53:28 BC", as found
+// in query results, CSV exports and logical replication messages. Years may
+// have more than four digits, and years BC are returned as the years 0 and
+// before; "infinity" and "-infinity" are returned as InfinityTime and
+// NegInfinityTime.
+//
+// Values with a time zone offset are returned in loc if it has the same
+// offset at that instant, and otherwise in a fixed zone with that offset,
+// or in UTC if the offset is zero. loc may be nil. Values without a time
+// zone are returned in UTC.
+func ParseTimestamp(loc *time.Location, s string) (time.Time, error) {
+	return parseTs(loc, s)
+}
+
+// RFC3339Timestamptz is an RFC 3339 timestamp in a string, such as
+// "2012-11-06T10:23:42.5-07:00", for binding as a timestamptz parameter.
+// Rather than being sent as it is, which the server might read differently
+// depending on its DateStyle, it is parsed and sent in the ISO format, like
+// a time.Time.
+type RFC3339Timestamptz string
+
+// Value implements the driver Valuer interface.
+func (ts RFC3339Timestamptz) Value() (driver.Value, error) {
+	t, err := time.Parse(time.RFC3339Nano, string(ts))
+	if err != nil {
+		return nil, fmt.Errorf("pq: cannot parse %q as an RFC 3339 timestamp", string(ts))
+	}
+	return t, nil
+}
+
+// WallClockTimestamp is a time.Time to be bound to a timestamp parameter by
+// its wall clock: it is sent without its zone offset, so that the value
+// stored is the date and time of day t shows, whatever the session's
+// TimeZone. A time.Time is sent with its offset, which a timestamptz
+// parameter, or one cast from one, converts to the session's zone.
+type WallClockTimestamp time.Time
+
+// Value implements the driver Valuer interface.
+func (ts WallClockTimestamp) Value() (driver.Value, error) {
+	t := time.Time(ts)
+	switch {
+	case t.Equal(InfinityTime):
+		return "infinity", nil
+	case t.Equal(NegInfinityTime):
+		return "-infinity", nil
+	}
+	return string(formatTs(t, false)), nil
+}
+
+// parseTs implements ParseTimestamp, telling dates, timestamps and
+// timestamptzs apart by their shape.
+func parseTs(loc *time.Location, str string) (time.Time, error) {
+	switch str {
+	case "infinity":
+		return InfinityTime, nil
+	case "-infinity":
+		return NegInfinityTime, nil
+	}
+
+	p := newTsParser(str)
+	bc := strings.HasSuffix(p.s, " BC")
+	if bc {
+		p.s = p.s[:len(p.s)-len(" BC")]
+	}
+
+	year := p.digits(4, 7)
+	p.expect('-')
+	month := p.field(1, 12)
+	p.expect('-')
+	day := p.field(1, 31)
+
+	var hour, min, sec, nsec, offset int
+	hasTz := false
+	if p.peek(' ') {
+		p.expect(' ')
+		hour, min, sec, nsec = p.clock(23)
+		offset, hasTz = p.zone()
+	}
+
+	p.end()
+	if !p.ok {
+		return time.Time{}, p.error("timestamp")
+	}
+	if bc {
+		year = 1 - year
+	}
+
+	t := time.Date(year, time.Month(month), day, hour, min, sec, nsec, time.UTC)
+	if !hasTz {
+		return t, nil
+	}
+	return inZone(t, offset, loc), nil
+}
+
+// parseTime parses a time such as "10:23:42.5" into a time on January 1 of
+// the year 0, in UTC, as time.Parse would. Fractional seconds of any length
+// are read exactly, up to the nanosecond. Postgres allows the time
+// 24:00:00, which becomes midnight on January 2.
+func parseTime(str string) (time.Time, error) {
+	p := newTsParser(str)
+	hour, min, sec, nsec := p.clock(24)
+	p.end()
+	if !p.ok {
+		return time.Time{}, p.error("time")
+	}
+	if hour == 24 && min+sec+nsec != 0 {
+		return time.Time{}, fmt.Errorf("pq: unable to parse time %q: it is later than 24:00:00", str)
+	}
+	return time.Date(0, time.January, 1, hour, min, sec, nsec, time.UTC), nil
+}
+
+// parseTimetz parses a timetz such as "10:23:42.5+05:30" into a time on
+// January 1 of the year 0, the date time.Parse uses when there is none.
+// Postgres allows the time 24:00:00, which becomes midnight on January 2.
+// As with ParseTimestamp, the time is returned in loc if it has the same
+// offset.
+func parseTimetz(loc *time.Location, str string) (time.Time, error) {
+	p := newTsParser(str)
+	hour, min, sec, nsec := p.clock(24)
+	offset, hasTz := p.zone()
+	if !hasTz {
+		p.fail("a time zone offset")
+	}
+	p.end()
+	if !p.ok {
+		return time.Time{}, p.error("timetz")
+	}
+	if hour == 24 && min+sec+nsec != 0 {
+		return time.Time{}, fmt.Errorf("pq: unable to parse timetz %q: it is later than 24:00:00", str)
+	}
+
+	t := time.Date(0, time.January, 1, hour, min, sec, nsec, time.UTC)
+	return inZone(t, offset, loc), nil
+}
+
+// clock reads a time of day of the form hh:mm:ss[.f], where the hour is no
+// greater than maxHour.
+func (p *tsParser) clock(maxHour int) (hour, min, sec, nsec int) {
+	hour = p.field(0, maxHour)
+	p.expect(':')
+	min = p.field(0, 59)
+	p.expect(':')
+	sec = p.field(0, 59)
+	if p.peek('.') {
+		p.expect('.')
+		nsec = p.nanoseconds()
+	}
+	return
+}
+
+// zone reads an optional time zone offset of the form +hh[:mm[:ss]] or
+// -hh[:mm[:ss]] into seconds east of UTC, reporting whether there was one.
+func (p *tsParser) zone() (offset int, ok bool) {
+	if !p.peek('+') && !p.peek('-') {
+		return 0, false
+	}
+	sign := 1
+	if p.peek('-') {
+		sign = -1
+	}
+	p.s = p.s[1:]
+
+	offset = p.field(0, 99) * 60 * 60
+	if p.peek(':') {
+		p.expect(':')
+		offset += p.field(0, 59) * 60
+	}
+	if p.peek(':') {
+		p.expect(':')
+		offset += p.field(0, 59)
+	}
+	return sign * offset, true
+}
+
+// inZone converts t, the wall clock time in a zone offset seconds east of
+// UTC, into an instant in that zone. Like time.Parse does with the local
+// time zone, it prefers loc, if not nil, when that has the same offset at
+// that instant; loc is only ever consulted for that instant, so a zone
+// whose offset changes, as with daylight saving time, is used only on the
+// side of the change where it agrees. Otherwise, a zero offset is returned
+// as UTC, rather than as a fixed zone with no name, and any other offset as
+// such a fixed zone.
+func inZone(t time.Time, offset int, loc *time.Location) time.Time {
+	t = t.Add(-time.Duration(offset) * time.Second)
+	if loc != nil {
+		if _, locOffset := t.In(loc).Zone(); locOffset == offset {
+			return t.In(loc)
+		}
+	}
+	if offset == 0 {
+		return t.UTC()
+	}
+	return t.In(time.FixedZone("", offset))
+}
@@ -0,0 +1,424 @@
+package pq
+
+import (
+	"github.com/lib/pq/oid"
+	"testing"
+	"time"
+)
+
+func TestParseTs(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  time.Time
+	}{
+		{"2012-11-06", time.Date(2012, 11, 6, 0, 0, 0, 0, time.UTC)},
+		{"10000-01-01", time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"294276-12-31 23:59:59.999999", time.Date(294276, 12, 31, 23, 59, 59, 999999000, time.UTC)},
+		{"2012-11-06 10:23:42", time.Date(2012, 11, 6, 10, 23, 42, 0, time.UTC)},
+		{"2012-11-06 10:23:42.1", time.Date(2012, 11, 6, 10, 23, 42, 100000000, time.UTC)},
+		{"2012-11-06 10:23:42.1234567891", time.Date(2012, 11, 6, 10, 23, 42, 123456789, time.UTC)},
+		{"2012-11-06 10:23:42.1234567885", time.Date(2012, 11, 6, 10, 23, 42, 123456789, time.UTC)},
+		{"2012-11-06 10:23:42.12345678849", time.Date(2012, 11, 6, 10, 23, 42, 123456788, time.UTC)},
+		{"2012-11-06 10:23:59.9999999999", time.Date(2012, 11, 6, 10, 24, 0, 0, time.UTC)},
+		{"2012-12-31 23:59:59.99999999951+00", time.Date(2013, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"2012-11-06 10:23:42.05", time.Date(2012, 11, 6, 10, 23, 42, 50000000, time.UTC)},
+		{"12345-11-06 10:23:42-07", time.Date(12345, 11, 6, 17, 23, 42, 0, time.UTC)},
+		{"2012-11-06 10:23:42+05:30", time.Date(2012, 11, 6, 4, 53, 42, 0, time.UTC)},
+		{"2012-11-06 10:23:42+05:45", time.Date(2012, 11, 6, 4, 38, 42, 0, time.UTC)},
+		{"2012-11-06 10:23:42+00:00", time.Date(2012, 11, 6, 10, 23, 42, 0, time.UTC)},
+		{"1880-01-01 00:00:00+00:53:28", time.Date(1879, 12, 31, 23, 6, 32, 0, time.UTC)},
+		{"0001-01-01 BC", time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0044-03-15 12:00:00+00 BC", time.Date(-43, 3, 15, 12, 0, 0, 0, time.UTC)},
+		{"infinity", InfinityTime},
+		{"-infinity", NegInfinityTime},
+	} {
+		got, err := parseTs(nil, tt.input)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("%q: expected %v, got %v", tt.input, tt.want, got)
+		}
+	}
+}
+
+func TestParseTimestampLocation(t *testing.T) {
+	est := time.FixedZone("EST", -5*60*60)
+
+	got, err := ParseTimestamp(est, "2012-11-06 10:23:42-05")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Location() != est {
+		t.Errorf("expected the time in EST, got %v", got)
+	}
+
+	got, err = ParseTimestamp(est, "2012-11-06 10:23:42+01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, offset := got.Zone(); name != "" || offset != 60*60 {
+		t.Errorf("expected a fixed +01 zone, got %v", got)
+	}
+
+	got, err = ParseTimestamp(nil, "2012-11-06 10:23:42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Location() != time.UTC {
+		t.Errorf("expected the time in UTC, got %v", got)
+	}
+
+	if _, err := ParseTimestamp(est, "2012-11-06 10:23"); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestParseTsError(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"201-11-06",
+		"2012-1-06",
+		"2012-13-06",
+		"2012-11-00",
+		"2012-11-06 ",
+		"2012-11-06 10:23",
+		"2012-11-06 10:60:00",
+		"2012-11-06 10:23:42.",
+		"2012-11-06 10:23:42+5",
+		"2012-11-06 10:23:42+05:",
+		"2012-11-06 10:23:42 +05",
+		"2012-11-06 BCE",
+		"2012-11-06x",
+	} {
+		if _, err := parseTs(nil, input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestParseTsErrorMessage(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  string
+	}{
+		{"", `pq: unable to parse timestamp "": expected 4 to 7 digits at offset 0`},
+		{"11/06/2012", `pq: unable to parse timestamp "11/06/2012": expected 4 to 7 digits at offset 0`},
+		{"2012/11/06", `pq: unable to parse timestamp "2012/11/06": expected '-' at offset 4`},
+		{"2012-13-06", `pq: unable to parse timestamp "2012-13-06": expected a number from 01 to 12 at offset 5`},
+		{"2012-11-00", `pq: unable to parse timestamp "2012-11-00": expected a number from 01 to 31 at offset 8`},
+		{"2012-11-06 10:23", `pq: unable to parse timestamp "2012-11-06 10:23": expected ':' at offset 16`},
+		{"2012-11-06 10:23:4", `pq: unable to parse timestamp "2012-11-06 10:23:4": expected 2 digits at offset 17`},
+		{"2012-11-06 10:23:42 PST", `pq: unable to parse timestamp "2012-11-06 10:23:42 PST": expected the end of the value at offset 19`},
+	} {
+		_, err := parseTs(nil, tt.input)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("%q: expected %s, got %v", tt.input, tt.want, err)
+		}
+	}
+
+	if _, err := parseTimetz(nil, "10:23:42"); err == nil || err.Error() != `pq: unable to parse timetz "10:23:42": expected a time zone offset at offset 8` {
+		t.Errorf("unexpected error %v", err)
+	}
+	if _, err := parseTime("24:00:01"); err == nil || err.Error() != `pq: unable to parse time "24:00:01": it is later than 24:00:00` {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestTimestampWideYear(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var got time.Time
+	err := db.QueryRow("SELECT '10000-01-01'::timestamp").Scan(&got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  time.Time
+	}{
+		{"10:23:42", time.Date(0, 1, 1, 10, 23, 42, 0, time.UTC)},
+		{"10:23:42.5", time.Date(0, 1, 1, 10, 23, 42, 500000000, time.UTC)},
+		{"10:23:42.05", time.Date(0, 1, 1, 10, 23, 42, 50000000, time.UTC)},
+		{"10:23:42.123", time.Date(0, 1, 1, 10, 23, 42, 123000000, time.UTC)},
+		{"10:23:42.1234", time.Date(0, 1, 1, 10, 23, 42, 123400000, time.UTC)},
+		{"10:23:42.12345", time.Date(0, 1, 1, 10, 23, 42, 123450000, time.UTC)},
+		{"10:23:42.123456", time.Date(0, 1, 1, 10, 23, 42, 123456000, time.UTC)},
+		{"00:00:00", time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"24:00:00", time.Date(0, 1, 2, 0, 0, 0, 0, time.UTC)},
+	} {
+		got := mustDecode(t, &parameterStatus{}, []byte(tt.input), oid.T_time, formatText)
+		if got != tt.want {
+			t.Errorf("%q: expected %v, got %v", tt.input, tt.want, got)
+		}
+	}
+
+	for _, input := range []string{"", "10:23", "10:23:42.", "10:23:42+00", "10:60:00", "24:00:00.5", "25:00:00"} {
+		if _, err := parseTime(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestWallClockTimestampValue(t *testing.T) {
+	for _, tt := range []struct {
+		t    time.Time
+		want string
+	}{
+		{time.Date(2012, 11, 6, 10, 23, 42, 0, time.FixedZone("", -7*60*60)), "2012-11-06 10:23:42"},
+		{time.Date(2012, 11, 6, 10, 23, 42, 123456789, time.UTC), "2012-11-06 10:23:42.123457"},
+		{time.Date(-43, 3, 15, 12, 0, 0, 0, time.FixedZone("", 5*60*60)), "0044-03-15 12:00:00 BC"},
+		{InfinityTime, "infinity"},
+		{NegInfinityTime, "-infinity"},
+	} {
+		got, err := WallClockTimestamp(tt.t).Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%v: expected %q, got %q", tt.t, tt.want, got)
+		}
+	}
+}
+
+func TestWallClockTimestampParameter(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	in := time.Date(2012, 11, 6, 10, 23, 42, 0, time.FixedZone("", -7*60*60))
+	var text string
+	err := db.QueryRow("SELECT $1::timestamptz::timestamp::text", WallClockTimestamp(in)).Scan(&text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "2012-11-06 10:23:42" {
+		t.Errorf("expected the wall clock time, got %q", text)
+	}
+}
+
+func TestParseTimetz(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  time.Time
+	}{
+		{"10:23:42+00", time.Date(0, 1, 1, 10, 23, 42, 0, time.UTC)},
+		{"10:23:42-07", time.Date(0, 1, 1, 17, 23, 42, 0, time.UTC)},
+		{"10:23:42.789+05:30", time.Date(0, 1, 1, 4, 53, 42, 789000000, time.UTC)},
+		{"10:23:42.5+05:30", time.Date(0, 1, 1, 4, 53, 42, 500000000, time.UTC)},
+		{"10:23:42+05:45", time.Date(0, 1, 1, 4, 38, 42, 0, time.UTC)},
+		{"10:23:42-09:30", time.Date(0, 1, 1, 19, 53, 42, 0, time.UTC)},
+		{"10:23:42+00:00", time.Date(0, 1, 1, 10, 23, 42, 0, time.UTC)},
+		{"10:23:42+00:53:28", time.Date(0, 1, 1, 9, 30, 14, 0, time.UTC)},
+		{"10:23:42.123456-00:00:30", time.Date(0, 1, 1, 10, 24, 12, 123456000, time.UTC)},
+		{"24:00:00+00", time.Date(0, 1, 2, 0, 0, 0, 0, time.UTC)},
+	} {
+		got, err := parseTimetz(nil, tt.input)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("%q: expected %v, got %v", tt.input, tt.want, got)
+		}
+	}
+
+	for _, input := range []string{
+		"",
+		"10:23:42",
+		"10:23+05",
+		"10:23:42.+05",
+		"10:23:42+5",
+		"10:23:42+05:3",
+		"24:00:01+00",
+		"25:00:00+00",
+	} {
+		if _, err := parseTimetz(nil, input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestParseTsZoneAcrossDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	for _, tt := range []struct {
+		input string
+		loc   *time.Location
+		want  string // the zone's name, or "" for an unnamed fixed zone
+	}{
+		// New York moved from EST (-05) to EDT (-04) at 2012-03-11
+		// 07:00:00 UTC; times at UTC are never in New York.
+		{"2012-03-11 06:59:59+00", ny, "UTC"},
+		{"2012-03-11 07:00:00+00", ny, "UTC"},
+		{"2012-03-11 01:59:59-05", ny, "EST"},
+		{"2012-03-11 03:00:00-04", ny, "EDT"},
+		{"2012-03-11 03:00:00-05", ny, ""},
+		{"2012-03-11 01:59:59-04", ny, ""},
+
+		// London moved from GMT (+00) to BST (+01) at 2012-03-25
+		// 01:00:00 UTC, so times at UTC are only in London before then.
+		{"2012-03-25 00:59:59+00", london, "GMT"},
+		{"2012-03-25 01:00:00+00", london, "UTC"},
+		{"2012-03-25 02:00:00+01", london, "BST"},
+		{"2012-03-25 00:30:00+01", london, ""},
+
+		{"2012-03-25 01:00:00+00", nil, "UTC"},
+		{"2012-03-25 01:00:00-00", nil, "UTC"},
+	} {
+		got, err := parseTs(tt.loc, tt.input)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, err)
+			continue
+		}
+		name, _ := got.Zone()
+		if name != tt.want {
+			t.Errorf("%q in %v: expected the zone %q, got %q", tt.input, tt.loc, tt.want, name)
+		}
+		if tt.want == "UTC" && got.Location() != time.UTC {
+			t.Errorf("%q in %v: expected time.UTC, got %v", tt.input, tt.loc, got.Location())
+		}
+	}
+}
+
+func TestParseOddOffsets(t *testing.T) {
+	for _, tt := range []struct {
+		input  string
+		offset int
+	}{
+		{"10:23:42+05:45", 5*60*60 + 45*60},
+		{"10:23:42-09:30", -(9*60*60 + 30*60)},
+		{"10:23:42+00:00", 0},
+		{"10:23:42+00:53:28", 53*60 + 28},
+	} {
+		got, err := parseTimetz(nil, tt.input)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if _, offset := got.Zone(); offset != tt.offset {
+			t.Errorf("%q: expected offset %d, got %d", tt.input, tt.offset, offset)
+		}
+		if got.Hour() != 10 || got.Minute() != 23 || got.Second() != 42 {
+			t.Errorf("%q: expected the wall clock to be kept, got %v", tt.input, got)
+		}
+
+		ts, err := parseTs(nil, "2012-11-06 "+tt.input)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if _, offset := ts.Zone(); offset != tt.offset {
+			t.Errorf("%q: expected offset %d, got %d", tt.input, tt.offset, offset)
+		}
+	}
+}
+
+func TestTimetzOffsets(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	for _, tt := range []struct {
+		input string
+		want  time.Time
+	}{
+		{"10:23:42.789+05:30", time.Date(0, 1, 1, 4, 53, 42, 789000000, time.UTC)},
+		{"10:23:42+05:45", time.Date(0, 1, 1, 4, 38, 42, 0, time.UTC)},
+		{"10:23:42+00:00", time.Date(0, 1, 1, 10, 23, 42, 0, time.UTC)},
+	} {
+		var got time.Time
+		err := db.QueryRow("SELECT $1::timetz", tt.input).Scan(&got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("%q: expected %v, got %v", tt.input, tt.want, got)
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("SET LOCAL TimeZone = 'Asia/Kathmandu'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ts time.Time
+	err = tx.QueryRow("SELECT '2012-11-06 04:38:42+00'::timestamptz").Scan(&ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, offset := ts.Zone(); offset != 5*60*60+45*60 {
+		t.Errorf("expected offset +05:45, got %d", offset)
+	}
+	if ts.Hour() != 10 || ts.Minute() != 23 {
+		t.Errorf("expected the wall clock 10:23, got %v", ts)
+	}
+}
+
+func TestRFC3339TimestamptzValue(t *testing.T) {
+	for _, tt := range []struct {
+		input RFC3339Timestamptz
+		want  string
+	}{
+		{"2012-11-06T10:23:42Z", "2012-11-06 10:23:42Z"},
+		{"2012-11-06T10:23:42.5-07:00", "2012-11-06 10:23:42.5-07:00:00"},
+		{"2012-11-06T10:23:42+05:45", "2012-11-06 10:23:42+05:45:00"},
+	} {
+		v, err := tt.input.Value()
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got := string(mustEncode(t, v, oid.T_timestamptz)); got != tt.want {
+			t.Errorf("%q: expected %q, got %q", tt.input, tt.want, got)
+		}
+	}
+
+	for _, input := range []RFC3339Timestamptz{"", "2012-11-06", "2012-11-06 10:23:42", "06/11/2012 10:23:42Z"} {
+		if _, err := input.Value(); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestRFC3339TimestamptzParameter(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("SET LOCAL DateStyle TO 'SQL, DMY'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ok bool
+	err = tx.QueryRow("SELECT $1::timestamptz = '2012-11-06 17:23:42+00'", RFC3339Timestamptz("2012-11-06T10:23:42-07:00")).Scan(&ok)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Error("expected the timestamp to be read as November 6")
+	}
+}
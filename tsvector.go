@@ -0,0 +1,192 @@
+package pq
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+)
+
+// TSVector represents a Postgres tsvector, a sorted list of distinct
+// lexemes. A nil TSVector is NULL. tsvector columns are decoded as text, so
+// that they can be scanned into a string; scan them into a TSVector to
+// parse them.
+type TSVector []Lexeme
+
+// Lexeme is a word of a TSVector, with the positions it occurs at in the
+// document, if they were kept.
+type Lexeme struct {
+	Word      string
+	Positions []LexemePosition
+}
+
+// LexemePosition is a position of a Lexeme, between 1 and 16383, and its
+// weight, one of 'A', 'B', 'C' or 'D'. Postgres leaves out the weight D,
+// the default, when printing positions.
+type LexemePosition struct {
+	Position uint16
+	Weight   byte
+}
+
+// Scan implements the Scanner interface.
+func (v *TSVector) Scan(value interface{}) error {
+	var s string
+	switch value := value.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case TSVector:
+		*v = value
+		return nil
+	case []byte:
+		s = string(value)
+	case string:
+		s = value
+	default:
+		return fmt.Errorf("pq: cannot scan %T into TSVector", value)
+	}
+
+	tv, err := parseTSVector(s)
+	if err != nil {
+		return err
+	}
+	*v = tv
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (v TSVector) Value() (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	var b []byte
+	for i, l := range v {
+		if i > 0 {
+			b = append(b, ' ')
+		}
+		b = append(b, '\'')
+		for j := 0; j < len(l.Word); j++ {
+			if c := l.Word[j]; c == '\'' || c == '\\' {
+				b = append(b, c)
+			}
+			b = append(b, l.Word[j])
+		}
+		b = append(b, '\'')
+		for j, p := range l.Positions {
+			if j == 0 {
+				b = append(b, ':')
+			} else {
+				b = append(b, ',')
+			}
+			b = strconv.AppendUint(b, uint64(p.Position), 10)
+			if p.Weight != 0 && p.Weight != 'D' {
+				b = append(b, p.Weight)
+			}
+		}
+	}
+	return string(b), nil
+}
+
+// parseTSVector parses the text form of a tsvector, such as
+// "'a':1A 'cat':2,5". Lexemes may also be unquoted. A backslash escapes the
+// next character in either kind, and in quoted ones, a doubled single
+// quote stands for one.
+func parseTSVector(s string) (TSVector, error) {
+	fail := func() (TSVector, error) {
+		return nil, fmt.Errorf("pq: unable to parse tsvector %q", s)
+	}
+
+	v := TSVector{}
+	i := 0
+	for {
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		if i == len(s) {
+			return v, nil
+		}
+
+		var word []byte
+		quoted := s[i] == '\''
+		if quoted {
+			i++
+		}
+		for ; i < len(s); i++ {
+			c := s[i]
+			if c == '\\' {
+				if i++; i == len(s) {
+					return fail()
+				}
+				c = s[i]
+			} else if quoted && c == '\'' {
+				if i+1 < len(s) && s[i+1] == '\'' {
+					i++
+				} else {
+					break
+				}
+			} else if !quoted && (c == ' ' || c == ':') {
+				break
+			}
+			word = append(word, c)
+		}
+		if quoted {
+			if i == len(s) {
+				return fail()
+			}
+			i++
+		}
+		if len(word) == 0 {
+			return fail()
+		}
+		l := Lexeme{Word: string(word)}
+
+		for i < len(s) && (s[i] == ':' || (s[i] == ',' && l.Positions != nil)) {
+			i++
+			start := i
+			for i < len(s) && '0' <= s[i] && s[i] <= '9' {
+				i++
+			}
+			pos, err := strconv.ParseUint(s[start:i], 10, 16)
+			if err != nil {
+				return fail()
+			}
+			p := LexemePosition{Position: uint16(pos), Weight: 'D'}
+			if i < len(s) {
+				switch c := s[i] &^ 0x20; c {
+				case 'A', 'B', 'C', 'D':
+					p.Weight = c
+					i++
+				}
+			}
+			l.Positions = append(l.Positions, p)
+		}
+		if i < len(s) && s[i] != ' ' {
+			return fail()
+		}
+		v = append(v, l)
+	}
+}
+
+// TSQuery represents a Postgres tsquery, such as `'fat' & ( 'rat' | 'cat' )`.
+// Its values are returned as text, which can be scanned into a TSQuery.
+type TSQuery string
+
+// Scan implements the Scanner interface.
+func (q *TSQuery) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case TSQuery:
+		*q = v
+	case []byte:
+		*q = TSQuery(v)
+	case string:
+		*q = TSQuery(v)
+	default:
+		return fmt.Errorf("pq: cannot scan %T into TSQuery", value)
+	}
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (q TSQuery) Value() (driver.Value, error) {
+	return string(q), nil
+}
@@ -0,0 +1,118 @@
+package pq
+
+import (
+	"github.com/lib/pq/oid"
+	"reflect"
+	"testing"
+)
+
+func TestParseTSVector(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  TSVector
+	}{
+		{``, TSVector{}},
+		{`'cat'`, TSVector{{Word: "cat"}}},
+		{`'cat':1 'dog':2,3`, TSVector{
+			{"cat", []LexemePosition{{1, 'D'}}},
+			{"dog", []LexemePosition{{2, 'D'}, {3, 'D'}}},
+		}},
+		{`'a':1A,2b 'c':3C,4`, TSVector{
+			{"a", []LexemePosition{{1, 'A'}, {2, 'B'}}},
+			{"c", []LexemePosition{{3, 'C'}, {4, 'D'}}},
+		}},
+		{`'it''s' 'back\\slash' 'sp ace:1'`, TSVector{{Word: "it's"}, {Word: `back\slash`}, {Word: "sp ace:1"}}},
+		{`fat:2 rat`, TSVector{{"fat", []LexemePosition{{2, 'D'}}}, {Word: "rat"}}},
+	} {
+		var got TSVector
+		if err := got.Scan([]byte(tt.input)); err != nil {
+			t.Errorf("%q: %s", tt.input, err)
+		} else if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: expected %#v, got %#v", tt.input, tt.want, got)
+		}
+	}
+
+	got := mustDecode(t, &parameterStatus{}, []byte(`'cat':1`), oid.T_tsvector, formatText)
+	if b, ok := got.([]byte); !ok || string(b) != `'cat':1` {
+		t.Errorf("expected the text of the tsvector, got %#v", got)
+	}
+
+	for _, input := range []string{`''`, `'cat`, `'cat':`, `'cat':x`, `'cat':1,`, `'cat'x`, `cat\`, `'cat':70000`} {
+		if _, err := parseTSVector(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestTSVectorValue(t *testing.T) {
+	v := TSVector{
+		{"a", []LexemePosition{{1, 'A'}, {2, 'D'}}},
+		{`it's \`, nil},
+	}
+	got, err := v.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `'a':1A,2 'it''s \\'`; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	back, err := parseTSVector(got.(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, v) {
+		t.Errorf("expected %#v, got %#v", v, back)
+	}
+
+	if got, _ := TSVector(nil).Value(); got != nil {
+		t.Errorf("expected a nil TSVector to be NULL, got %#v", got)
+	}
+}
+
+func TestTSVectorScan(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var v TSVector
+	err := db.QueryRow("SELECT setweight(to_tsvector('simple', 'fat cats ate fat rats'), 'B')").Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := TSVector{
+		{"ate", []LexemePosition{{3, 'B'}}},
+		{"cats", []LexemePosition{{2, 'B'}}},
+		{"fat", []LexemePosition{{1, 'B'}, {4, 'B'}}},
+		{"rats", []LexemePosition{{5, 'B'}}},
+	}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("expected %#v, got %#v", want, v)
+	}
+
+	var s string
+	err = db.QueryRow("SELECT to_tsvector('simple', 'fat cat')").Scan(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "'cat':2 'fat':1" {
+		t.Errorf("expected the text of the tsvector, got %q", s)
+	}
+
+	var ok bool
+	err = db.QueryRow("SELECT $1::tsvector = $2::tsvector", want, "ate:3B cats:2B fat:1B,4B rats:5B").Scan(&ok)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Error("expected the TSVector to round trip")
+	}
+
+	var q TSQuery
+	err = db.QueryRow("SELECT $1::tsquery", TSQuery("fat & (rat | cat)")).Scan(&q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q != `'fat' & ( 'rat' | 'cat' )` {
+		t.Errorf("unexpected tsquery %q", q)
+	}
+}
@@ -0,0 +1,78 @@
+package pq
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+)
+
+// UUID is a uuid value. Such columns are decoded as their canonical text,
+// so that they can be scanned into a string; scan them into a UUID to get
+// their 16 bytes.
+type UUID [16]byte
+
+// String returns the canonical text form of the UUID.
+func (u UUID) String() string {
+	return string(encodeUUID(u))
+}
+
+// Scan implements the Scanner interface.
+func (u *UUID) Scan(value interface{}) error {
+	var s []byte
+	switch v := value.(type) {
+	case []byte:
+		s = v
+	case string:
+		s = []byte(v)
+	default:
+		return fmt.Errorf("pq: cannot scan %T into UUID", value)
+	}
+
+	b, err := decodeUUID(s)
+	if err != nil {
+		return err
+	}
+	copy(u[:], b)
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (u UUID) Value() (driver.Value, error) {
+	return u.String(), nil
+}
+
+// decodeUUID parses the canonical text form of a UUID, as sent by the
+// server, into its 16 bytes.
+func decodeUUID(s []byte) ([]byte, error) {
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return nil, fmt.Errorf("pq: unable to parse UUID %q", s)
+	}
+
+	digits := make([]byte, 0, 32)
+	digits = append(digits, s[:8]...)
+	digits = append(digits, s[9:13]...)
+	digits = append(digits, s[14:18]...)
+	digits = append(digits, s[19:23]...)
+	digits = append(digits, s[24:]...)
+
+	u := make([]byte, 16)
+	if _, err := hex.Decode(u, digits); err != nil {
+		return nil, fmt.Errorf("pq: unable to parse UUID %q", s)
+	}
+	return u, nil
+}
+
+// encodeUUID renders u in the canonical text form of a UUID.
+func encodeUUID(u [16]byte) []byte {
+	b := make([]byte, 36)
+	hex.Encode(b[0:8], u[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], u[10:])
+	return b
+}
@@ -0,0 +1,74 @@
+package pq
+
+import (
+	"github.com/lib/pq/oid"
+	"testing"
+)
+
+var testUUID = [16]byte{
+	0xa0, 0xee, 0xbc, 0x99, 0x9c, 0x0b, 0x4e, 0xf8,
+	0xbb, 0x6d, 0x6b, 0xb9, 0xbd, 0x38, 0x0a, 0x11,
+}
+
+func TestDecodeUUID(t *testing.T) {
+	got := mustDecode(t, &parameterStatus{}, []byte("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"), oid.T_uuid, formatText)
+	if b, ok := got.([]byte); !ok || string(b) != "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11" {
+		t.Errorf("expected the text of the UUID, got %#v", got)
+	}
+}
+
+func TestUUIDScan(t *testing.T) {
+	var u UUID
+	if err := u.Scan([]byte("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")); err != nil {
+		t.Fatal(err)
+	}
+	if u != testUUID {
+		t.Errorf("unexpected UUID %x", u)
+	}
+	if v, err := u.Value(); err != nil || v != "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11" {
+		t.Errorf("unexpected value %#v, %v", v, err)
+	}
+
+	for _, input := range []interface{}{
+		"",
+		"a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a1",
+		"a0eebc999c0b4ef8bb6d6bb9bd380a11",
+		"a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a1x",
+		"a0eebc99-9c0b-4ef8-bb6d_6bb9bd380a11",
+		int64(1),
+		nil,
+	} {
+		if err := u.Scan(input); err == nil {
+			t.Errorf("%#v: expected an error", input)
+		}
+	}
+}
+
+func TestEncodeUUID(t *testing.T) {
+	got := string(mustEncode(t, testUUID, oid.T_uuid))
+	if got != "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11" {
+		t.Errorf("unexpected encoding %q", got)
+	}
+}
+
+func TestUUIDRoundTrip(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var got UUID
+	var s string
+	var b []byte
+	err := db.QueryRow("SELECT $1::uuid, $1::uuid, $2::uuid", testUUID, UUID(testUUID)).Scan(&got, &s, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != testUUID {
+		t.Errorf("expected %x, got %x", testUUID, got)
+	}
+	if s != "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11" {
+		t.Errorf("expected the text of the UUID, got %q", s)
+	}
+	if string(b) != s {
+		t.Errorf("expected the text of the UUID, got %q", b)
+	}
+}
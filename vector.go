@@ -0,0 +1,104 @@
+package pq
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"github.com/lib/pq/oid"
+	"strconv"
+	"strings"
+)
+
+// Int2Vector represents a Postgres int2vector, as found in catalog columns
+// such as pg_index.indkey. int2vector columns are decoded as text, so that
+// they can be scanned into a string; scan them into an Int2Vector to parse
+// them.
+type Int2Vector []int16
+
+// Scan implements the Scanner interface.
+func (v *Int2Vector) Scan(value interface{}) error {
+	a, err := scanVector(value, oid.T_int2vector, "Int2Vector")
+	if err != nil {
+		return err
+	}
+	s := make(Int2Vector, len(a))
+	for i, n := range a {
+		s[i] = int16(n)
+	}
+	*v = s
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (v Int2Vector) Value() (driver.Value, error) {
+	var b []byte
+	for i, n := range v {
+		if i > 0 {
+			b = append(b, ' ')
+		}
+		b = strconv.AppendInt(b, int64(n), 10)
+	}
+	return string(b), nil
+}
+
+// OIDVector represents a Postgres oidvector, as found in catalog columns
+// such as pg_proc.proargtypes. oidvector columns are decoded as text, so
+// that they can be scanned into a string; scan them into an OIDVector to
+// parse them.
+type OIDVector []oid.Oid
+
+// Scan implements the Scanner interface.
+func (v *OIDVector) Scan(value interface{}) error {
+	a, err := scanVector(value, oid.T_oidvector, "OIDVector")
+	if err != nil {
+		return err
+	}
+	s := make(OIDVector, len(a))
+	for i, n := range a {
+		s[i] = oid.Oid(n)
+	}
+	*v = s
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (v OIDVector) Value() (driver.Value, error) {
+	var b []byte
+	for i, n := range v {
+		if i > 0 {
+			b = append(b, ' ')
+		}
+		b = strconv.AppendUint(b, uint64(n), 10)
+	}
+	return string(b), nil
+}
+
+func scanVector(value interface{}, typ oid.Oid, name string) ([]int64, error) {
+	switch v := value.(type) {
+	case []byte:
+		return parseVector(string(v), typ)
+	case string:
+		return parseVector(v, typ)
+	}
+	return nil, fmt.Errorf("pq: cannot scan %T into %s", value, name)
+}
+
+// parseVector parses an int2vector or oidvector, a list of integers
+// separated by spaces.
+func parseVector(s string, typ oid.Oid) ([]int64, error) {
+	fields := strings.Fields(s)
+	v := make([]int64, len(fields))
+	for i, f := range fields {
+		var err error
+		if typ == oid.T_int2vector {
+			v[i], err = strconv.ParseInt(f, 10, 16)
+		} else {
+			var u uint64
+			u, err = strconv.ParseUint(f, 10, 32)
+			v[i] = int64(u)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("pq: unable to parse vector %q", s)
+		}
+	}
+	return v, nil
+}
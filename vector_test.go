@@ -0,0 +1,103 @@
+package pq
+
+import (
+	"github.com/lib/pq/oid"
+	"reflect"
+	"testing"
+)
+
+func TestParseVector(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		typ   oid.Oid
+		want  []int64
+	}{
+		{"", oid.T_int2vector, []int64{}},
+		{"1 3 -2", oid.T_int2vector, []int64{1, 3, -2}},
+		{" 7 ", oid.T_int2vector, []int64{7}},
+		{"23 4294967295", oid.T_oidvector, []int64{23, 4294967295}},
+	} {
+		got, err := parseVector(tt.input, tt.typ)
+		if err != nil {
+			t.Errorf("%q: %s", tt.input, err)
+		} else if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: expected %#v, got %#v", tt.input, tt.want, got)
+		}
+	}
+
+	for _, tt := range []struct {
+		input string
+		typ   oid.Oid
+	}{
+		{"1 x", oid.T_int2vector},
+		{"40000", oid.T_int2vector},
+		{"-1", oid.T_oidvector},
+	} {
+		if _, err := parseVector(tt.input, tt.typ); err == nil {
+			t.Errorf("%q: expected an error", tt.input)
+		}
+	}
+
+	got := mustDecode(t, &parameterStatus{}, []byte("1 3"), oid.T_int2vector, formatText)
+	if b, ok := got.([]byte); !ok || string(b) != "1 3" {
+		t.Errorf("expected the text of the vector, got %#v", got)
+	}
+}
+
+func TestVectorScanValue(t *testing.T) {
+	var iv Int2Vector
+	if err := iv.Scan([]byte("1 3 -2")); err != nil {
+		t.Fatal(err)
+	}
+	if want := (Int2Vector{1, 3, -2}); !reflect.DeepEqual(iv, want) {
+		t.Errorf("expected %v, got %v", want, iv)
+	}
+	if v, _ := iv.Value(); v != "1 3 -2" {
+		t.Errorf("unexpected value %#v", v)
+	}
+
+	var ov OIDVector
+	if err := ov.Scan("23 4294967295"); err != nil {
+		t.Fatal(err)
+	}
+	if want := (OIDVector{23, 4294967295}); !reflect.DeepEqual(ov, want) {
+		t.Errorf("expected %v, got %v", want, ov)
+	}
+	if v, _ := ov.Value(); v != "23 4294967295" {
+		t.Errorf("unexpected value %#v", v)
+	}
+
+	if err := iv.Scan(nil); err == nil {
+		t.Error("expected an error scanning NULL")
+	}
+	if err := ov.Scan("-1"); err == nil {
+		t.Error("expected an error for a negative oid")
+	}
+}
+
+func TestScanVector(t *testing.T) {
+	db := openTestConn(t)
+	defer db.Close()
+
+	var keys Int2Vector
+	var s string
+	err := db.QueryRow("SELECT '1 3'::int2vector, '1 3'::int2vector").Scan(&keys, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(keys, Int2Vector{1, 3}) {
+		t.Errorf("expected [1 3], got %v", keys)
+	}
+	if s != "1 3" {
+		t.Errorf("expected the text of the vector, got %q", s)
+	}
+
+	var types OIDVector
+	err = db.QueryRow("SELECT $1::oidvector", OIDVector{oid.T_int4, oid.T_text}).Scan(&types)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(types, OIDVector{oid.T_int4, oid.T_text}) {
+		t.Errorf("unexpected oidvector %v", types)
+	}
+}